@@ -10,6 +10,20 @@ import (
 // CrawlerConfig stores the configuration for the crawler.
 type CrawlerConfig struct {
 	Agent string `mapstructure:"agent"`
+
+	// Optional ceilings for the projects' crawl limit and max depth.
+	// Zero means there is no ceiling.
+	MaxCrawlLimit int `mapstructure:"max_crawl_limit"`
+	MaxDepth      int `mapstructure:"max_depth"`
+
+	// Optional default proxy URL for the projects that don't set their own.
+	Proxy string `mapstructure:"proxy"`
+
+	// Number of days before a TLS certificate expires to start reporting it.
+	CertExpiryDays int `mapstructure:"cert_expiry_days"`
+
+	// Min similarity percentage of the pages' main text to report them as near-duplicates.
+	NearDuplicateSimilarity int `mapstructure:"near_duplicate_similarity"`
 }
 
 // HTTPServerConfig stores the configuration for the HTTP server.
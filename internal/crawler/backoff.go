@@ -0,0 +1,141 @@
+package crawler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// Max number of times a throttled request is queued again.
+	maxThrottleRetries = 3
+
+	// Retry-After delays are capped so a single response can't stall the crawl.
+	maxRetryAfter = 2 * time.Minute
+
+	// Delay used if the throttling response doesn't have a valid Retry-After header.
+	// It is doubled with every retry of the same request.
+	defaultRetryAfter = 5 * time.Second
+
+	// Min interval between requests to a host once it has throttled the crawler.
+	// It is doubled every time the host throttles the crawler again.
+	minBackoffInterval = time.Second
+
+	// Number of consecutive successful responses from a throttled host before the interval
+	// between requests is halved.
+	backoffRecoveryRun = 10
+)
+
+// Backoff keeps track of the hosts that are throttling the crawler. Requests to a throttled
+// host are paused until its Retry-After time has passed and from then on they are spaced out
+// by an interval that grows every time the host throttles the crawler again and shrinks after
+// a run of successful responses.
+type Backoff struct {
+	hosts map[string]*hostBackoff
+	lock  *sync.Mutex
+}
+
+type hostBackoff struct {
+	next      time.Time
+	interval  time.Duration
+	successes int // Consecutive successful responses since the interval was last changed.
+}
+
+func NewBackoff() *Backoff {
+	return &Backoff{
+		hosts: make(map[string]*hostBackoff),
+		lock:  &sync.Mutex{},
+	}
+}
+
+// Throttle pauses the requests to the host for the duration d and increases the interval
+// between the following requests to the host.
+func (b *Backoff) Throttle(host string, d time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	h, ok := b.hosts[host]
+	if !ok {
+		h = &hostBackoff{}
+		b.hosts[host] = h
+	}
+
+	h.interval = min(max(2*h.interval, minBackoffInterval), maxCrawlDelay)
+	h.successes = 0
+
+	until := time.Now().Add(d)
+	if until.After(h.next) {
+		h.next = until
+	}
+}
+
+// Success records a successful response from the host. After a run of successful responses
+// the interval between requests to a throttled host is halved, and once it is below the min
+// interval the host is no longer throttled.
+func (b *Backoff) Success(host string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	h, ok := b.hosts[host]
+	if !ok {
+		return
+	}
+
+	h.successes++
+	if h.successes < backoffRecoveryRun {
+		return
+	}
+
+	h.successes = 0
+	h.interval /= 2
+	if h.interval < minBackoffInterval && time.Now().After(h.next) {
+		delete(b.hosts, host)
+	}
+}
+
+// Wait blocks until a request to the host is allowed or the context is cancelled.
+// It returns false if the context was cancelled while waiting.
+func (b *Backoff) Wait(ctx context.Context, host string) bool {
+	b.lock.Lock()
+	h, ok := b.hosts[host]
+	if !ok {
+		b.lock.Unlock()
+		return ctx.Err() == nil
+	}
+
+	now := time.Now()
+	if h.next.Before(now) {
+		h.next = now
+	}
+
+	wait := h.next.Sub(now)
+	h.next = h.next.Add(h.interval)
+	b.lock.Unlock()
+
+	return sleep(ctx, wait)
+}
+
+// isThrottled returns true if the response status code means the server is
+// rate limiting the crawler or it is temporarily overloaded.
+func isThrottled(r *http.Response) bool {
+	return r != nil && (r.StatusCode == http.StatusTooManyRequests || r.StatusCode == http.StatusServiceUnavailable)
+}
+
+// retryAfter returns the delay before a throttled request can be retried. The Retry-After
+// header can be either a number of seconds or an HTTP date. If it is missing or not valid the
+// default delay is doubled with every retry.
+func retryAfter(r *http.Response, retries int) time.Duration {
+	d := defaultRetryAfter << retries
+
+	v := strings.TrimSpace(r.Header.Get("Retry-After"))
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		d = time.Duration(seconds) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = max(time.Until(t), 0)
+	}
+
+	return min(d, maxRetryAfter)
+}
@@ -0,0 +1,110 @@
+package crawler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// TestBackoffThrottle tests that only the requests to the throttled host are paused.
+func TestBackoffThrottle(t *testing.T) {
+	pause := 50 * time.Millisecond
+	b := crawler.NewBackoff()
+	b.Throttle("throttled.example.com", pause)
+	ctx := context.Background()
+
+	start := time.Now()
+	b.Wait(ctx, "example.com")
+	if elapsed := time.Since(start); elapsed >= pause {
+		t.Errorf("Request to a host that is not throttled took %v", elapsed)
+	}
+
+	b.Wait(ctx, "throttled.example.com")
+	if elapsed := time.Since(start); elapsed < pause {
+		t.Errorf("Request to a throttled host took %v, expected at least %v", elapsed, pause)
+	}
+}
+
+// TestBackoffCancel tests that a cancelled context stops the wait.
+func TestBackoffCancel(t *testing.T) {
+	b := crawler.NewBackoff()
+	b.Throttle("example.com", time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if b.Wait(ctx, "example.com") {
+		t.Error("Wait returned true with a cancelled context")
+	}
+}
+
+// TestBackoffSuccess tests that a host is no longer throttled after a run of successful
+// responses.
+func TestBackoffSuccess(t *testing.T) {
+	b := crawler.NewBackoff()
+	b.Throttle("example.com", 0)
+	for range 10 {
+		b.Success("example.com")
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	b.Wait(ctx, "example.com")
+	b.Wait(ctx, "example.com")
+	if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+		t.Errorf("Requests to a recovered host took %v", elapsed)
+	}
+}
+
+// TestCrawlerRetriesThrottled tests that a throttled URL is queued again and only the
+// response after the retry is sent to the callback.
+func TestCrawlerRetriesThrottled(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL + "/")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{}, &http.Client{Timeout: 5 * time.Second})
+	c := crawler.NewCrawler(u, &crawler.Options{CrawlLimit: 10, IgnoreRobotsTxt: true, Timeout: 10 * time.Second}, client)
+
+	statusCodes := []int{}
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		if r.Response != nil {
+			statusCodes = append(statusCodes, r.Response.StatusCode)
+		}
+	})
+
+	c.AddRequest(&crawler.RequestMessage{URL: u})
+	c.Start()
+
+	if len(statusCodes) != 1 || statusCodes[0] != http.StatusOK {
+		t.Errorf("expected a single 200 response, got %v", statusCodes)
+	}
+
+	if c.Throttled() != 1 {
+		t.Errorf("expected 1 throttled response, got %d", c.Throttled())
+	}
+}
@@ -1,13 +1,32 @@
 package crawler
 
 import (
-	"math"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
+	"sync"
+	"syscall"
 	"time"
 )
 
+const (
+	// Default delay before retrying a request. It is doubled with every attempt.
+	defaultRetryDelay = time.Second
+
+	// Max delay between attempts of the same request.
+	maxRetryDelay = 10 * time.Second
+
+	// Max size of the response body in bytes. Larger bodies are truncated, the
+	// same limit is used when the body is parsed.
+	maxBodySize = 10 * 1024 * 1024
+)
+
 type HTTPRequester interface {
 	Do(req *http.Request) (*http.Response, error)
 }
@@ -15,6 +34,9 @@ type HTTPRequester interface {
 type BasicClient struct {
 	Options *ClientOptions
 	client  HTTPRequester
+
+	loginLock *sync.Mutex
+	loginGen  int // Incremented on every successful login.
 }
 
 type ClientOptions struct {
@@ -22,18 +44,58 @@ type ClientOptions struct {
 	BasicAuthDomains []string
 	AuthUser         string
 	AuthPass         string
+
+	// Custom headers and cookies are only sent to the CustomDomains, so they
+	// don't leak to external hosts.
+	CustomDomains []string
+	Headers       http.Header
+	Cookies       []*http.Cookie
+
+	// Login describes a form login run before the crawl, nil if the site
+	// doesn't need it. The session is kept by the http client's cookie jar.
+	Login *LoginOptions
+
+	// Number of times GET and HEAD requests are retried after a timeout or a transient
+	// network error. RetryDelay is the delay before the first retry, defaultRetryDelay
+	// is used if it is zero.
+	Retries    int
+	RetryDelay time.Duration
 }
 
 func NewBasicClient(options *ClientOptions, client HTTPRequester) *BasicClient {
 	return &BasicClient{
-		Options: options,
-		client:  client,
+		Options:   options,
+		client:    client,
+		loginLock: &sync.Mutex{},
 	}
 }
 
 // Makes a request with the method specified in the method parameter to the specified URL.
-func (c *BasicClient) request(method, urlStr string) (*ClientResponse, error) {
-	req, err := http.NewRequest(method, urlStr, nil)
+// If the response shows the form login session was lost, it logs in again and repeats
+// the request.
+func (c *BasicClient) request(ctx context.Context, method, urlStr string) (*ClientResponse, error) {
+	gen := c.loginGeneration()
+
+	cr, err := c.send(ctx, method, urlStr, nil)
+	if err != nil || !c.isLoggedOut(cr) {
+		return cr, err
+	}
+
+	if err := c.relogin(ctx, gen); err != nil {
+		log.Printf("relogin: %v\n", err)
+		return cr, nil
+	}
+
+	cr.Response.Body.Close()
+
+	return c.send(ctx, method, urlStr, nil)
+}
+
+// send makes a request adding the basic auth credentials, custom headers and cookies
+// if the URL's domain is one of the configured domains. A non nil body is sent as an
+// url encoded form. The request is cancelled along with the context.
+func (c *BasicClient) send(ctx context.Context, method, urlStr string, body io.Reader) (*ClientResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, body)
 	if err != nil {
 		return nil, err
 	}
@@ -43,17 +105,31 @@ func (c *BasicClient) request(method, urlStr string) (*ClientResponse, error) {
 		return nil, err
 	}
 
-	if c.Options.AuthUser != "" && c.isBasicAuthDomain(domain.Host) {
+	if c.Options.AuthUser != "" && hasDomain(c.Options.BasicAuthDomains, domain.Host) {
 		req.SetBasicAuth(c.Options.AuthUser, c.Options.AuthPass)
 	}
 
+	if hasDomain(c.Options.CustomDomains, domain.Host) {
+		for name, values := range c.Options.Headers {
+			req.Header[name] = append([]string(nil), values...)
+		}
+
+		for _, cookie := range c.Options.Cookies {
+			req.AddCookie(cookie)
+		}
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
 	return c.do(req)
 }
 
-// Returns true if the domain exists in the BasicAutDomains slice.
-func (c *BasicClient) isBasicAuthDomain(domain string) bool {
-	for _, authDomain := range c.Options.BasicAuthDomains {
-		if authDomain == domain {
+// Returns true if the domain exists in the domains slice.
+func hasDomain(domains []string, domain string) bool {
+	for _, d := range domains {
+		if d == domain {
 			return true
 		}
 	}
@@ -62,41 +138,96 @@ func (c *BasicClient) isBasicAuthDomain(domain string) bool {
 }
 
 // Makes a GET request to an URL and returns the http response or an error.
-func (c *BasicClient) Get(urlStr string) (*ClientResponse, error) {
-	return c.request(http.MethodGet, urlStr)
+func (c *BasicClient) Get(ctx context.Context, urlStr string) (*ClientResponse, error) {
+	return c.request(ctx, http.MethodGet, urlStr)
 }
 
 // Makes a HEAD request to an URL and returns the http response or an error.
-func (c *BasicClient) Head(urlStr string) (*ClientResponse, error) {
-	return c.request(http.MethodHead, urlStr)
+func (c *BasicClient) Head(ctx context.Context, urlStr string) (*ClientResponse, error) {
+	return c.request(ctx, http.MethodHead, urlStr)
 }
 
 // do executes a request and returns its response and error.
-// It sets the client's User-Agent as well as the BasicAuth details if they are available.
+// It sets the client's User-Agent and retries GET and HEAD requests that fail with a transient
+// error, waiting longer after each attempt. It stops retrying if the request's context is
+// cancelled. The returned response has the number of attempts even if all of them failed.
 func (c *BasicClient) do(req *http.Request) (*ClientResponse, error) {
-	cr := &ClientResponse{}
+	req.Header.Set("User-Agent", c.Options.UserAgent)
 
-	start := time.Now()
-	trace := &httptrace.ClientTrace{
-		GotFirstResponseByte: func() {
-			// Time To First Byte in milliseconds
-			cr.TTFB = int(math.Ceil(float64(time.Since(start) / time.Millisecond)))
-		},
+	retries := 0
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		retries = c.Options.Retries
 	}
 
-	req.Header.Set("User-Agent", c.Options.UserAgent)
-	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+	delay := c.Options.RetryDelay
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		cr, err := c.attempt(req)
+		cr.Attempts = attempt
+		if err == nil || attempt > retries || !isTransient(err) {
+			return cr, err
+		}
 
-	resp, err := c.client.Do(req)
+		if !sleep(req.Context(), min(delay<<(attempt-1), maxRetryDelay)) {
+			return cr, err
+		}
+	}
+}
+
+// attempt makes a single request measuring the duration of each of its phases and keeping the
+// server's TLS certificate, even if it failed the verification. Up to maxBodySize bytes of the
+// response body are read so the download time is measured and errors reading it can be retried
+// as well. The rest of the body is not downloaded.
+func (c *BasicClient) attempt(req *http.Request) (*ClientResponse, error) {
+	cr := &ClientResponse{}
+	t := newTimer()
+
+	resp, err := c.client.Do(req.Clone(httptrace.WithClientTrace(req.Context(), t.trace())))
 	if err != nil {
-		return nil, err
+		cr.Timing = t.timing(nil)
+		cr.Certificate = certificateFromError(req.URL.Hostname(), err)
+		return cr, err
+	}
+
+	cr.Certificate = certificateFromState(req.URL.Hostname(), resp.TLS)
+
+	if resp.Body != nil {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		resp.Body.Close()
+		t.done()
+		if err != nil {
+			cr.Timing = t.timing(resp)
+			return cr, err
+		}
+
+		resp.Body = io.NopCloser(bytes.NewReader(body))
 	}
 
 	cr.Response = resp
+	cr.Timing = t.timing(resp)
 
 	return cr, nil
 }
 
+// isTransient returns true if the error is a timeout, a DNS failure or a connection that was
+// reset or closed by the server, which may not happen again if the request is retried.
+func isTransient(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
 // GetUA returns the user-agent set for this client.
 func (c *BasicClient) GetUA() string {
 	return c.Options.UserAgent
@@ -1,10 +1,16 @@
 package crawler_test
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/crawler"
 )
@@ -36,7 +42,7 @@ func TestGetUserAgent(t *testing.T) {
 	mockClient := &mockClient{}
 	client := crawler.NewBasicClient(options, mockClient)
 
-	_, err := client.Get("http://example.com")
+	_, err := client.Get(context.Background(), "http://example.com")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -61,7 +67,7 @@ func TestHeadUserAgent(t *testing.T) {
 	mockClient := &mockClient{}
 	client := crawler.NewBasicClient(options, mockClient)
 
-	_, err := client.Head("http://example.com")
+	_, err := client.Head(context.Background(), "http://example.com")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -90,7 +96,7 @@ func TestBasicAuthHeadersSent(t *testing.T) {
 	mockClient := &mockClient{}
 	client := crawler.NewBasicClient(options, mockClient)
 
-	_, err := client.Get("http://example.com")
+	_, err := client.Get(context.Background(), "http://example.com")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -118,7 +124,7 @@ func TestBasicAuthHeadersNotSent(t *testing.T) {
 	mockClient := &mockClient{}
 	client := crawler.NewBasicClient(options, mockClient)
 
-	_, err := client.Get("http://example.com")
+	_, err := client.Get(context.Background(), "http://example.com")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -140,8 +146,196 @@ func TestHTTPError(t *testing.T) {
 	mockClient := &mockClient{ForceError: true}
 	client := crawler.NewBasicClient(options, mockClient)
 
-	_, err := client.Get("http://example.com")
+	_, err := client.Get(context.Background(), "http://example.com")
 	if err == nil {
 		t.Fatal("expected an error, got none")
 	}
 }
+
+// Test custom headers and cookies are only sent to the CustomDomains.
+func TestCustomHeadersAndCookies(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Accept-Language", "es")
+	headers.Set("X-Bypass-Token", "secret")
+
+	options := &crawler.ClientOptions{
+		UserAgent:     "TEST_UA",
+		CustomDomains: []string{"example.com"},
+		Headers:       headers,
+		Cookies:       []*http.Cookie{{Name: "consent", Value: "yes"}},
+	}
+
+	mockClient := &mockClient{}
+	client := crawler.NewBasicClient(options, mockClient)
+
+	table := []struct {
+		url  string
+		sent bool
+	}{
+		{"http://example.com/page", true},
+		{"http://external.com/page", false},
+	}
+
+	for _, tc := range table {
+		_, err := client.Get(context.Background(), tc.url)
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+
+		token := mockClient.lastRequest.Header.Get("X-Bypass-Token")
+		if (token == "secret") != tc.sent {
+			t.Errorf("%s: X-Bypass-Token header '%s', expected sent %v", tc.url, token, tc.sent)
+		}
+
+		_, err = mockClient.lastRequest.Cookie("consent")
+		if (err == nil) != tc.sent {
+			t.Errorf("%s: consent cookie error %v, expected sent %v", tc.url, err, tc.sent)
+		}
+
+		if mockClient.lastRequest.Header.Get("User-Agent") != "TEST_UA" {
+			t.Errorf("%s: User-Agent header was overwritten", tc.url)
+		}
+	}
+}
+
+// failingClient fails the first failures requests with err.
+type failingClient struct {
+	failures int
+	err      error
+	requests int
+}
+
+func (m *failingClient) Do(req *http.Request) (*http.Response, error) {
+	m.requests++
+	if m.requests <= m.failures {
+		return nil, m.err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK}, nil
+}
+
+// Test GET and HEAD requests are retried after transient errors but not after other errors.
+func TestRetries(t *testing.T) {
+	timeout := &net.OpError{Op: "read", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+
+	table := []struct {
+		name         string
+		method       string
+		failures     int
+		err          error
+		wantAttempts int
+		wantError    bool
+	}{
+		{"timeout retried", http.MethodGet, 2, timeout, 3, false},
+		{"dns failure retried", http.MethodHead, 1, &net.DNSError{Err: "no such host", IsNotFound: true}, 2, false},
+		{"too many failures", http.MethodGet, 5, timeout, 4, true},
+		{"not transient", http.MethodGet, 1, fmt.Errorf("mock error"), 1, true},
+	}
+
+	for _, tc := range table {
+		t.Run(tc.name, func(t *testing.T) {
+			mockClient := &failingClient{failures: tc.failures, err: tc.err}
+			client := crawler.NewBasicClient(&crawler.ClientOptions{Retries: 3, RetryDelay: time.Millisecond}, mockClient)
+
+			var cr *crawler.ClientResponse
+			var err error
+			switch tc.method {
+			case http.MethodGet:
+				cr, err = client.Get(context.Background(), "http://example.com")
+			case http.MethodHead:
+				cr, err = client.Head(context.Background(), "http://example.com")
+			}
+
+			if (err != nil) != tc.wantError {
+				t.Errorf("want error %v got %v", tc.wantError, err)
+			}
+
+			if cr.Attempts != tc.wantAttempts {
+				t.Errorf("want %d attempts got %d", tc.wantAttempts, cr.Attempts)
+			}
+		})
+	}
+}
+
+// Test the retries stop waiting when the request's context is cancelled.
+func TestRetriesCancelled(t *testing.T) {
+	timeout := &net.OpError{Op: "read", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+	mockClient := &failingClient{failures: 5, err: timeout}
+	client := crawler.NewBasicClient(&crawler.ClientOptions{Retries: 3, RetryDelay: time.Hour}, mockClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	cr, err := client.Get(ctx, "http://example.com")
+	if err == nil {
+		t.Error("want error got nil")
+	}
+
+	if cr.Attempts != 1 {
+		t.Errorf("want 1 attempt got %d", cr.Attempts)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("the retry wait was not cancelled, took %v", elapsed)
+	}
+}
+
+// Test the response body is truncated to the max body size.
+func TestBodySize(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte("a"), 11*1024*1024))
+	}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{}, ts.Client())
+	r, err := client.Get(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(r.Response.Body)
+	if len(body) != 10*1024*1024 {
+		t.Errorf("want a body of 10MB got %d bytes", len(body))
+	}
+}
+
+// Test the request timing over a TLS connection with a slow response body.
+func TestTiming(t *testing.T) {
+	pause := 20 * time.Millisecond
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "first")
+		w.(http.Flusher).Flush()
+		time.Sleep(pause)
+		io.WriteString(w, "second")
+	}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{}, ts.Client())
+	r, err := client.Get(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := io.ReadAll(r.Response.Body)
+	if string(body) != "firstsecond" {
+		t.Errorf("body: want 'firstsecond' got '%s'", body)
+	}
+
+	timing := r.Timing
+	if timing.Download < int(pause/time.Millisecond) {
+		t.Errorf("Download: want at least %v got %dms", pause, timing.Download)
+	}
+
+	if timing.TTFB <= 0 || timing.TLSHandshake <= 0 {
+		t.Errorf("TTFB and TLSHandshake should be set: %+v", timing)
+	}
+
+	if timing.Protocol != "HTTP/1.1" {
+		t.Errorf("Protocol: want HTTP/1.1 got %s", timing.Protocol)
+	}
+
+	if timing.TLSVersion == "" {
+		t.Error("TLSVersion should be set")
+	}
+}
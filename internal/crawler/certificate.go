@@ -0,0 +1,61 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"time"
+)
+
+// Certificate holds the details of the TLS certificate presented by a host.
+type Certificate struct {
+	Host             string
+	Subject          string
+	Issuer           string
+	NotBefore        time.Time
+	NotAfter         time.Time
+	DNSNames         []string
+	Valid            bool   // True if the certificate chain was verified.
+	HostnameMismatch bool   // True if the certificate is not valid for the host.
+	Error            string // The verification error if the chain was not verified.
+}
+
+// certificateFromState returns the certificate of a TLS connection or nil if the
+// server didn't present any certificate.
+func certificateFromState(host string, state *tls.ConnectionState) *Certificate {
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil
+	}
+
+	c := newCertificate(host, state.PeerCertificates[0])
+	c.Valid = len(state.VerifiedChains) > 0
+
+	return c
+}
+
+// certificateFromError returns the certificate that failed the verification in the
+// error, or nil if the error is not a certificate verification error.
+func certificateFromError(host string, err error) *Certificate {
+	var verificationErr *tls.CertificateVerificationError
+	if !errors.As(err, &verificationErr) || len(verificationErr.UnverifiedCertificates) == 0 {
+		return nil
+	}
+
+	c := newCertificate(host, verificationErr.UnverifiedCertificates[0])
+	c.Error = verificationErr.Err.Error()
+
+	return c
+}
+
+// newCertificate returns a Certificate with the details of the host's leaf certificate.
+func newCertificate(host string, leaf *x509.Certificate) *Certificate {
+	return &Certificate{
+		Host:             host,
+		Subject:          leaf.Subject.String(),
+		Issuer:           leaf.Issuer.String(),
+		NotBefore:        leaf.NotBefore,
+		NotAfter:         leaf.NotAfter,
+		DNSNames:         leaf.DNSNames,
+		HostnameMismatch: leaf.VerifyHostname(host) != nil,
+	}
+}
@@ -0,0 +1,88 @@
+package crawler_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// Test the certificate details are recorded with a valid certificate, a certificate signed by an
+// unknown authority and a certificate that doesn't match the host name.
+func TestCertificate(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	u, err := url.Parse(ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The test server's certificate is valid for example.com and the loopback addresses.
+	localhostURL := "https://localhost:" + u.Port()
+
+	table := []struct {
+		name         string
+		url          string
+		httpClient   *http.Client
+		wantError    bool
+		wantValid    bool
+		wantMismatch bool
+	}{
+		{name: "valid", url: ts.URL, httpClient: ts.Client(), wantValid: true},
+		{name: "unknown authority", url: ts.URL, httpClient: &http.Client{}, wantError: true},
+		{name: "hostname mismatch", url: localhostURL, httpClient: ts.Client(), wantError: true, wantMismatch: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			client := crawler.NewBasicClient(&crawler.ClientOptions{}, tt.httpClient)
+			r, err := client.Get(context.Background(), tt.url)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("want error %v got %v", tt.wantError, err)
+			}
+
+			c := r.Certificate
+			if c == nil {
+				t.Fatal("the certificate was not recorded")
+			}
+
+			if c.Valid != tt.wantValid || c.HostnameMismatch != tt.wantMismatch {
+				t.Errorf("want valid %v mismatch %v got %+v", tt.wantValid, tt.wantMismatch, c)
+			}
+
+			if tt.wantError && c.Error == "" {
+				t.Error("the verification error was not recorded")
+			}
+
+			if !strings.Contains(strings.Join(c.DNSNames, ","), "example.com") || c.Issuer == "" || c.NotAfter.IsZero() {
+				t.Errorf("the certificate details are incomplete: %+v", c)
+			}
+		})
+	}
+}
+
+// Test no certificate is recorded for http URLs.
+func TestNoCertificate(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer ts.Close()
+
+	client := crawler.NewBasicClient(&crawler.ClientOptions{}, ts.Client())
+	r, err := client.Get(context.Background(), ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.Certificate != nil {
+		t.Errorf("want no certificate got %+v", r.Certificate)
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/stjudewashere/seonaut/internal/urlutils"
 )
 
 type Method int
@@ -18,24 +21,25 @@ const (
 	GET Method = iota
 	HEAD
 
-	// Random delay in milliseconds.
-	// A random delay up to this value is introduced before new HTTP requests.
-	randomDelay = 1500
+	// Default number of threads a queue will use to crawl a project.
+	defaultWorkers = 2
 
-	// Number of threads a queue will use to crawl a project.
-	consumerThreads = 2
+	// Default crawler timeout.
+	defaultTimeout = 2 * time.Hour
 
-	// Crawler timeout in hours.
-	crawlerTimeout = 2
+	// Max Crawl-delay from the robots.txt file the crawler will honour.
+	maxCrawlDelay = 60 * time.Second
 )
 
 var ErrBlockedByRobotstxt = errors.New("blocked by robots.txt")
 var ErrVisited = errors.New("URL already visited")
 var ErrDomainNotAllowed = errors.New("domain not allowed")
+var ErrMaxDepth = errors.New("max depth exceeded")
+var ErrOutOfScope = errors.New("URL out of crawl scope")
 
 type Client interface {
-	Get(urlStr string) (*ClientResponse, error)
-	Head(urlStr string) (*ClientResponse, error)
+	Get(ctx context.Context, urlStr string) (*ClientResponse, error)
+	Head(ctx context.Context, urlStr string) (*ClientResponse, error)
 	GetUA() string
 }
 
@@ -48,6 +52,22 @@ type Options struct {
 	IncludeNoindex  bool
 	CrawlSitemap    bool
 	AllowSubdomains bool
+	MaxDepth        int    // Max depth from the start URL, zero if there's no limit.
+	Scope           *Scope // Include and exclude rules, nil if all URLs are allowed.
+
+	// Normalizer is used to normalize the URLs before they are added to the crawler's
+	// queue. URLs are not normalized if it is nil.
+	Normalizer *urlutils.Normalizer
+
+	// Politeness settings. A random delay between MinDelay and MaxDelay is introduced
+	// by each worker before new requests, while MaxRequestsPerSecond limits the requests
+	// made by all the workers. The Crawl-delay directive in the robots.txt file is also
+	// honoured unless IgnoreRobotsTxt is set.
+	Workers              int
+	MinDelay             time.Duration
+	MaxDelay             time.Duration
+	MaxRequestsPerSecond float64
+	Timeout              time.Duration
 }
 
 type Status struct {
@@ -69,6 +89,9 @@ type Crawler struct {
 	sitemapIsBlocked bool
 	sitemaps         []string
 	robotsChecker    *RobotsChecker
+	limiter          *Limiter
+	backoff          *Backoff
+	throttled        atomic.Int64
 	allowedDomains   map[string]bool
 	mainDomain       string
 	cancel           context.CancelFunc
@@ -77,35 +100,68 @@ type Crawler struct {
 }
 
 type ClientResponse struct {
-	Response *http.Response
-	TTFB     int
+	Response    *http.Response
+	Timing      Timing
+	Certificate *Certificate // The server's TLS certificate, nil for http URLs.
+	Attempts    int          // Number of times the request was made.
 }
 
 type RequestMessage struct {
-	URL          *url.URL
-	IgnoreDomain bool
-	Method       Method
-	Data         interface{}
+	URL            *url.URL
+	IgnoreDomain   bool
+	IgnoreMaxDepth bool // Resources are crawled even if they are deeper than the max depth.
+	Method         Method
+	Depth          int // Depth from the start URL, negative if it is unknown.
+	Retries        int // Number of times the request was throttled and queued again.
+	Data           interface{}
 }
 
 type ResponseMessage struct {
-	URL       *url.URL
-	Response  *http.Response
-	Error     error
-	TTFB      int
-	Blocked   bool
-	InSitemap bool
-	Timeout   bool
-	Data      interface{}
+	URL         *url.URL
+	Response    *http.Response
+	Error       error
+	Timing      Timing
+	Certificate *Certificate
+	Blocked     bool
+	InSitemap   bool
+	Timeout     bool
+	Attempts    int
+	Depth       int // Depth of the request, negative if it is unknown.
+	Data        interface{}
+}
+
+// Checkpoint holds the crawler's frontier so an interrupted crawl can be restored
+// at a later time. Pending contains the requests that have not been processed yet
+// and Seen contains all the URLs that have been added to the crawler's storage.
+type Checkpoint struct {
+	Pending []*RequestMessage
+	Seen    []string
+	Crawled int
 }
 
 func NewCrawler(parsedURL *url.URL, options *Options, client Client) *Crawler {
+	if options.Normalizer != nil {
+		parsedURL = options.Normalizer.Normalize(parsedURL)
+	}
+
 	mainDomain := strings.TrimPrefix(parsedURL.Host, "www.")
 
 	robotsChecker := NewRobotsChecker(client)
 	sitemapChecker := NewSitemapChecker(client, options.CrawlLimit)
 
-	ctx, cancel := context.WithTimeout(context.Background(), crawlerTimeout*time.Hour)
+	if options.Workers < 1 {
+		options.Workers = defaultWorkers
+	}
+
+	if options.Timeout <= 0 {
+		options.Timeout = defaultTimeout
+	}
+
+	if options.MaxDelay < options.MinDelay {
+		options.MaxDelay = options.MinDelay
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.Timeout)
 
 	return &Crawler{
 		Client:         client,
@@ -117,6 +173,8 @@ func NewCrawler(parsedURL *url.URL, options *Options, client Client) *Crawler {
 		sitemapStorage: NewURLStorage(),
 		sitemapChecker: sitemapChecker,
 		robotsChecker:  robotsChecker,
+		limiter:        NewLimiter(rateInterval(options.MaxRequestsPerSecond)),
+		backoff:        NewBackoff(),
 		allowedDomains: map[string]bool{mainDomain: true, "www." + mainDomain: true},
 		mainDomain:     mainDomain,
 		cancel:         cancel,
@@ -137,9 +195,10 @@ func (c *Crawler) Start() {
 	defer c.cancel() // cancel the consumers so all channels are closed.
 
 	c.setupSitemaps()
+	c.setupCrawlDelay()
 
 	if c.sitemapExists && c.options.CrawlSitemap {
-		c.sitemapChecker.ParseSitemaps(c.sitemaps, c.loadSitemapURLs)
+		c.sitemapChecker.ParseSitemaps(c.context, c.sitemaps, c.loadSitemapURLs)
 	}
 
 	sitemapLoaded := false
@@ -156,7 +215,7 @@ func (c *Crawler) Start() {
 		c.queue.Ack(rm.URL.String())
 
 		rm.InSitemap = c.sitemapStorage.Seen(rm.URL.String())
-		rm.Blocked = c.robotsChecker.IsBlocked(rm.URL)
+		rm.Blocked = c.robotsChecker.IsBlocked(c.context, rm.URL)
 		rm.Timeout = rm.Error != nil
 
 		c.status.Crawled++
@@ -177,10 +236,15 @@ func (c *Crawler) Start() {
 }
 
 // AddRequest processes a request message for the crawler.
-// It checks if the URL has already been visited, validates the domain and checks
-// if it is blocked in the the robots.txt rules. It returns an error if any of the checks
-// fails. Finally, it adds the request to the processing queue.
+// The request's URL is normalized before any other check.
+// It checks if the URL has already been visited, validates the domain, the crawl scope and
+// the request's depth and checks if it is blocked in the the robots.txt rules. It returns an
+// error if any of the checks fails. Finally, it adds the request to the processing queue.
+// The scope rules don't apply to the start URL or to requests that ignore the domain, such
+// as resources.
 func (c *Crawler) AddRequest(r *RequestMessage) error {
+	r.URL = c.NormalizeURL(r.URL)
+
 	if c.storage.Seen(r.URL.String()) {
 		return ErrVisited
 	}
@@ -191,7 +255,15 @@ func (c *Crawler) AddRequest(r *RequestMessage) error {
 		return ErrDomainNotAllowed
 	}
 
-	if !c.options.IgnoreRobotsTxt && c.robotsChecker.IsBlocked(r.URL) {
+	if !r.IgnoreDomain && r.URL.String() != c.url.String() && !c.options.Scope.InScope(r.URL) {
+		return ErrOutOfScope
+	}
+
+	if c.options.MaxDepth > 0 && r.Depth > c.options.MaxDepth && !r.IgnoreMaxDepth {
+		return ErrMaxDepth
+	}
+
+	if !c.options.IgnoreRobotsTxt && c.robotsChecker.IsBlocked(c.context, r.URL) {
 		return ErrBlockedByRobotstxt
 	}
 
@@ -200,6 +272,46 @@ func (c *Crawler) AddRequest(r *RequestMessage) error {
 	return nil
 }
 
+// NormalizeURL returns the URL normalized with the crawler's normalizer, or the same URL
+// if the crawler doesn't have a normalizer.
+func (c *Crawler) NormalizeURL(u *url.URL) *url.URL {
+	if c.options.Normalizer == nil {
+		return u
+	}
+
+	return c.options.Normalizer.Normalize(u)
+}
+
+// Checkpoint returns the current state of the crawler's frontier. It must be called while
+// the crawler is running, for instance from the response callback.
+func (c *Crawler) Checkpoint() *Checkpoint {
+	cp := &Checkpoint{
+		Pending: c.queue.Snapshot(),
+		Crawled: c.status.Crawled,
+	}
+
+	c.storage.Iterate(func(v string) {
+		cp.Seen = append(cp.Seen, v)
+	})
+
+	return cp
+}
+
+// Restore loads a checkpoint into the crawler so the crawl continues where it was
+// interrupted. It must be called before the crawler is started.
+func (c *Crawler) Restore(cp *Checkpoint) {
+	for _, v := range cp.Seen {
+		c.storage.Add(v)
+	}
+
+	for _, r := range cp.Pending {
+		c.storage.Add(r.URL.String())
+		c.queue.Push(r)
+	}
+
+	c.status.Crawled = cp.Crawled
+}
+
 // GetStatus returns the current cralwer status.
 func (c *Crawler) GetStatus() Status {
 	c.status.Discovered = c.queue.Count()
@@ -208,6 +320,12 @@ func (c *Crawler) GetStatus() Status {
 	return c.status
 }
 
+// Throttled returns the number of responses in which the server was rate limiting the
+// crawler or was temporarily unavailable.
+func (c *Crawler) Throttled() int {
+	return int(c.throttled.Load())
+}
+
 // Returns true if the sitemap.xml file exists.
 func (c *Crawler) SitemapExists() bool {
 	return c.sitemapExists
@@ -215,7 +333,7 @@ func (c *Crawler) SitemapExists() bool {
 
 // Returns true if the robots.txt file exists.
 func (c *Crawler) RobotstxtExists() bool {
-	return c.robotsChecker.Exists(c.url)
+	return c.robotsChecker.Exists(c.context, c.url)
 }
 
 // Returns true if any of the website's sitemaps is blocked in the robots.txt file.
@@ -228,12 +346,17 @@ func (c *Crawler) Stop() {
 	c.cancel()
 }
 
+// Context returns the crawler's context, which is cancelled when the crawler stops.
+func (c *Crawler) Context() context.Context {
+	return c.context
+}
+
 // setupSitemaps checks if any sitemap exists for the crawler's url. It checks the robots file
 // as well as the default sitemap location. Afterwards it checks if the sitemap files are blocked
 // by the robots file. Any non-blocked sitemap is added to the crawler's sitemaps slice so it can
 // be loaded later on.
 func (c *Crawler) setupSitemaps() {
-	sitemaps := c.robotsChecker.GetSitemaps(c.url)
+	sitemaps := c.robotsChecker.GetSitemaps(c.context, c.url)
 	nonBlockedSitemaps := []string{}
 	if len(sitemaps) == 0 {
 		sitemaps = []string{c.url.Scheme + "://" + c.url.Host + "/sitemap.xml"}
@@ -245,7 +368,7 @@ func (c *Crawler) setupSitemaps() {
 			continue
 		}
 
-		if c.robotsChecker.IsBlocked(parsedSm) {
+		if c.robotsChecker.IsBlocked(c.context, parsedSm) {
 			c.sitemapIsBlocked = true
 			if !c.options.IgnoreRobotsTxt {
 				continue
@@ -256,7 +379,7 @@ func (c *Crawler) setupSitemaps() {
 	}
 
 	c.sitemaps = nonBlockedSitemaps
-	c.sitemapExists = c.sitemapChecker.SitemapExists(sitemaps)
+	c.sitemapExists = c.sitemapChecker.SitemapExists(c.context, sitemaps)
 }
 
 // crawl starts the request consumers in goroutines and polls URLs from the queue so they
@@ -266,10 +389,10 @@ func (c *Crawler) crawl() <-chan *ResponseMessage {
 	respStream := make(chan *ResponseMessage)
 
 	wg := new(sync.WaitGroup)
-	wg.Add(consumerThreads)
+	wg.Add(c.options.Workers)
 
 	// Starts the consumers that will make the client requests
-	for i := 0; i < consumerThreads; i++ {
+	for i := 0; i < c.options.Workers; i++ {
 		go func() {
 			defer wg.Done()
 			c.consumer(reqStream, respStream)
@@ -296,30 +419,61 @@ func (c *Crawler) crawl() <-chan *ResponseMessage {
 }
 
 // Consumer gets URLs from the reqStream until the context is cancelled.
-// It adds a random delay between client calls.
+// It adds a random delay between client calls and waits for the limiter
+// so the requests rate is not exceeded.
+// Throttling responses (429 and 503) pause the requests to the host and the request is
+// queued again, unless it has already been retried too many times. In that case the
+// response is sent to the respStream like any other response.
 func (c *Crawler) consumer(reqStream <-chan *RequestMessage, respStream chan<- *ResponseMessage) {
 	for {
 		select {
 		case requestMessage := <-reqStream:
 			// Add random delay to avoid overwhelming the servers with requests.
-			time.Sleep(time.Duration(rand.Intn(randomDelay)) * time.Millisecond)
+			delay := c.options.MinDelay
+			if c.options.MaxDelay > c.options.MinDelay {
+				delay += time.Duration(rand.Int63n(int64(c.options.MaxDelay - c.options.MinDelay)))
+			}
+
+			if !sleep(c.context, delay) || !c.limiter.Wait(c.context) || !c.backoff.Wait(c.context, requestMessage.URL.Host) {
+				return
+			}
 
 			rm := &ResponseMessage{
-				URL:  requestMessage.URL,
-				Data: requestMessage.Data,
+				URL:   requestMessage.URL,
+				Depth: requestMessage.Depth,
+				Data:  requestMessage.Data,
 			}
 
 			r := &ClientResponse{}
 			switch requestMessage.Method {
 			case GET:
-				r, rm.Error = c.Client.Get(requestMessage.URL.String())
+				r, rm.Error = c.Client.Get(c.context, requestMessage.URL.String())
 			case HEAD:
-				r, rm.Error = c.Client.Head(requestMessage.URL.String())
+				r, rm.Error = c.Client.Head(c.context, requestMessage.URL.String())
+			}
+
+			if r != nil {
+				rm.Attempts = r.Attempts
+				rm.Certificate = r.Certificate
 			}
 
 			if rm.Error == nil {
 				rm.Response = r.Response
-				rm.TTFB = r.TTFB
+				rm.Timing = r.Timing
+			}
+
+			if rm.Error == nil && isThrottled(rm.Response) {
+				c.throttled.Add(1)
+				c.backoff.Throttle(requestMessage.URL.Host, retryAfter(rm.Response, requestMessage.Retries))
+
+				if requestMessage.Retries < maxThrottleRetries {
+					rm.Response.Body.Close()
+					requestMessage.Retries++
+					c.queue.Retry(c.context, requestMessage)
+					continue
+				}
+			} else if rm.Error == nil {
+				c.backoff.Success(requestMessage.URL.Host)
 			}
 
 			respStream <- rm
@@ -329,6 +483,29 @@ func (c *Crawler) consumer(reqStream <-chan *RequestMessage, respStream chan<- *
 	}
 }
 
+// setupCrawlDelay checks the Crawl-delay directive in the robots.txt file. If it is set, the
+// limiter's interval is increased so there's at least that delay between requests.
+func (c *Crawler) setupCrawlDelay() {
+	if c.options.IgnoreRobotsTxt {
+		return
+	}
+
+	delay := min(c.robotsChecker.GetCrawlDelay(c.context, c.url), maxCrawlDelay)
+	if delay > rateInterval(c.options.MaxRequestsPerSecond) {
+		c.limiter.SetInterval(delay)
+	}
+}
+
+// rateInterval returns the minimum interval between requests for the specified rate.
+// It returns zero if the rate is not limited.
+func rateInterval(requestsPerSecond float64) time.Duration {
+	if requestsPerSecond <= 0 {
+		return 0
+	}
+
+	return time.Duration(float64(time.Second) / requestsPerSecond)
+}
+
 // Callback to load sitemap URLs into the sitemap storage.
 func (c *Crawler) loadSitemapURLs(u string) {
 	l, err := url.Parse(u)
@@ -340,21 +517,25 @@ func (c *Crawler) loadSitemapURLs(u string) {
 		l.Path = "/"
 	}
 
-	c.sitemapStorage.Add(l.String())
+	c.sitemapStorage.Add(c.NormalizeURL(l).String())
 }
 
-// queueSitemapURLs loops through the sitemap's URLs, adding any unseen URLs to the crawler's queue.
+// queueSitemapURLs loops through the sitemap's URLs, adding any unseen URLs that are in the
+// crawl scope to the crawler's queue. The sitemap URLs are crawled as seeds with a depth of
+// zero, the same as the start URL.
 func (c *Crawler) queueSitemapURLs() {
 	c.sitemapStorage.Iterate(func(v string) {
-		if !c.storage.Seen(v) {
-			c.storage.Add(v)
-			u, err := url.Parse(v)
-			if err != nil {
-				return
-			}
+		if c.storage.Seen(v) {
+			return
+		}
 
-			c.queue.Push(&RequestMessage{URL: u})
+		u, err := url.Parse(v)
+		if err != nil || !c.options.Scope.InScope(u) {
+			return
 		}
+
+		c.storage.Add(v)
+		c.queue.Push(&RequestMessage{URL: u, Depth: 0})
 	})
 }
 
@@ -0,0 +1,218 @@
+package crawler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+	"github.com/stjudewashere/seonaut/internal/urlutils"
+)
+
+// TestAddRequestMaxDepth tests that requests deeper than the MaxDepth option are refused,
+// except for the requests that ignore the max depth.
+func TestAddRequestMaxDepth(t *testing.T) {
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	c := crawler.NewCrawler(u, &crawler.Options{MaxDepth: 2}, &MockClient{})
+
+	table := []struct {
+		url            string
+		depth          int
+		ignoreMaxDepth bool
+		err            error
+	}{
+		{"https://example.com/depth-0", 0, false, nil},
+		{"https://example.com/depth-2", 2, false, nil},
+		{"https://example.com/depth-3", 3, false, crawler.ErrMaxDepth},
+		{"https://example.com/unknown-depth", -1, false, nil},
+		{"https://example.com/depth-3", 1, false, crawler.ErrVisited},
+		{"https://example.com/style.css", 3, true, nil},
+	}
+
+	for _, tc := range table {
+		r, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		err = c.AddRequest(&crawler.RequestMessage{URL: r, Depth: tc.depth, IgnoreMaxDepth: tc.ignoreMaxDepth})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s depth %d: error %v, expected %v", tc.url, tc.depth, err, tc.err)
+		}
+	}
+}
+
+// TestAddRequestScope tests that requests out of the crawl scope are refused,
+// except for the start URL and the requests that ignore the domain.
+func TestAddRequestScope(t *testing.T) {
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	scope, err := crawler.NewScope([]string{"/blog/"}, []string{"/blog/search"})
+	if err != nil {
+		t.Fatalf("NewScope error %v", err)
+	}
+
+	c := crawler.NewCrawler(u, &crawler.Options{Scope: scope}, &MockClient{})
+
+	table := []struct {
+		url          string
+		ignoreDomain bool
+		err          error
+	}{
+		{"https://example.com/", false, nil},
+		{"https://example.com/blog/post", false, nil},
+		{"https://example.com/blog/search?q=seo", false, crawler.ErrOutOfScope},
+		{"https://example.com/about", false, crawler.ErrOutOfScope},
+		{"https://example.com/style.css", true, nil},
+	}
+
+	for _, tc := range table {
+		r, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		err = c.AddRequest(&crawler.RequestMessage{URL: r, IgnoreDomain: tc.ignoreDomain})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: error %v, expected %v", tc.url, err, tc.err)
+		}
+	}
+}
+
+// TestAddRequestNormalizer tests that URLs are normalized before checking if they were visited.
+func TestAddRequestNormalizer(t *testing.T) {
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	normalizer := urlutils.NewNormalizer(urlutils.DefaultIgnoredParams, false)
+	c := crawler.NewCrawler(u, &crawler.Options{Normalizer: normalizer}, &MockClient{})
+
+	table := []struct {
+		url string
+		err error
+	}{
+		{"https://example.com/page?b=2&a=1", nil},
+		{"https://EXAMPLE.com/page?a=1&b=2#top", crawler.ErrVisited},
+		{"https://example.com/page?a=1&b=2&utm_source=newsletter", crawler.ErrVisited},
+		{"https://example.com/page?a=1", nil},
+	}
+
+	for _, tc := range table {
+		r, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		err = c.AddRequest(&crawler.RequestMessage{URL: r})
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: error %v, expected %v", tc.url, err, tc.err)
+		}
+	}
+}
+
+// sitemapClient is a mock client that serves a sitemap with URLs in and out of the crawl scope.
+type sitemapClient struct{}
+
+func (c *sitemapClient) Head(ctx context.Context, u string) (*crawler.ClientResponse, error) {
+	return c.Get(ctx, u)
+}
+
+func (c *sitemapClient) Get(ctx context.Context, u string) (*crawler.ClientResponse, error) {
+	r := &http.Response{StatusCode: http.StatusOK}
+	switch u {
+	case "https://example.com/robots.txt":
+		r.StatusCode = http.StatusNotFound
+		r.Body = io.NopCloser(strings.NewReader(""))
+	case "https://example.com/sitemap.xml":
+		r.Body = io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+		<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+			<url><loc>https://example.com/blog/post</loc></url>
+			<url><loc>https://example.com/blog/search</loc></url>
+			<url><loc>https://example.com/about</loc></url>
+		</urlset>`))
+	default:
+		r.Body = io.NopCloser(strings.NewReader(""))
+	}
+
+	return &crawler.ClientResponse{Response: r}, nil
+}
+
+func (c *sitemapClient) GetUA() string {
+	return "TEST UA"
+}
+
+// TestSitemapScope tests that the sitemap URLs out of the crawl scope are not crawled.
+func TestSitemapScope(t *testing.T) {
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	scope, err := crawler.NewScope([]string{"/blog/"}, []string{"/blog/search"})
+	if err != nil {
+		t.Fatalf("NewScope error %v", err)
+	}
+
+	options := &crawler.Options{Scope: scope, CrawlSitemap: true, CrawlLimit: 10}
+	c := crawler.NewCrawler(u, options, &sitemapClient{})
+
+	crawled := []string{}
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		crawled = append(crawled, r.URL.String())
+	})
+
+	c.Start()
+
+	expected := []string{"https://example.com/blog/post"}
+	if !slices.Equal(crawled, expected) {
+		t.Errorf("crawled %v, expected %v", crawled, expected)
+	}
+}
+
+// TestSitemapMaxDepth tests that the sitemap URLs are crawled at depth zero, so the pages
+// linked from them are limited by the MaxDepth option.
+func TestSitemapMaxDepth(t *testing.T) {
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatalf("url parse error %v", err)
+	}
+
+	options := &crawler.Options{MaxDepth: 1, CrawlSitemap: true, CrawlLimit: 10}
+	c := crawler.NewCrawler(u, options, &sitemapClient{})
+
+	// Every page links to a child page one level deeper, as the crawler handler does.
+	depths := map[string]int{}
+	c.OnResponse(func(r *crawler.ResponseMessage) {
+		depths[r.URL.Path] = r.Depth
+		c.AddRequest(&crawler.RequestMessage{URL: r.URL.JoinPath("child"), Depth: r.Depth + 1})
+	})
+
+	c.Start()
+
+	expected := map[string]int{
+		"/blog/post":         0,
+		"/blog/search":       0,
+		"/about":             0,
+		"/blog/post/child":   1,
+		"/blog/search/child": 1,
+		"/about/child":       1,
+	}
+	if !maps.Equal(depths, expected) {
+		t.Errorf("crawled %v, expected %v", depths, expected)
+	}
+}
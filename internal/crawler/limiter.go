@@ -0,0 +1,69 @@
+package crawler
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter spaces out the crawler's requests so there's at least a minimum interval
+// between them, regardless of the number of consumers making requests.
+type Limiter struct {
+	interval time.Duration
+	next     time.Time
+	lock     *sync.Mutex
+}
+
+func NewLimiter(interval time.Duration) *Limiter {
+	return &Limiter{
+		interval: interval,
+		lock:     &sync.Mutex{},
+	}
+}
+
+// SetInterval sets the minimum interval between requests.
+func (l *Limiter) SetInterval(interval time.Duration) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.interval = interval
+}
+
+// Wait blocks until the next request is allowed or the context is cancelled.
+// It returns false if the context was cancelled while waiting.
+func (l *Limiter) Wait(ctx context.Context) bool {
+	l.lock.Lock()
+	if l.interval <= 0 {
+		l.lock.Unlock()
+		return ctx.Err() == nil
+	}
+
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+
+	wait := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.lock.Unlock()
+
+	return sleep(ctx, wait)
+}
+
+// sleep pauses the current goroutine for the duration d or until the context is cancelled.
+// It returns false if the context was cancelled.
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,41 @@
+package crawler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// TestLimiterInterval tests that the limiter spaces out consecutive requests.
+func TestLimiterInterval(t *testing.T) {
+	interval := 20 * time.Millisecond
+	l := crawler.NewLimiter(interval)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if !l.Wait(ctx) {
+			t.Fatal("Wait returned false with an active context")
+		}
+	}
+
+	// The first request is not delayed, so three requests take at least two intervals.
+	if elapsed := time.Since(start); elapsed < 2*interval {
+		t.Errorf("Three requests took %v, expected at least %v", elapsed, 2*interval)
+	}
+}
+
+// TestLimiterCancel tests that a cancelled context stops the wait.
+func TestLimiterCancel(t *testing.T) {
+	l := crawler.NewLimiter(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	l.Wait(ctx)
+	cancel()
+
+	if l.Wait(ctx) {
+		t.Error("Wait returned true with a cancelled context")
+	}
+}
@@ -0,0 +1,201 @@
+package crawler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+// Max number of redirects followed after posting the login form.
+const maxLoginRedirects = 5
+
+var ErrLoginFailed = errors.New("login failed")
+
+// LoginOptions describes a login form. The form is loaded from URL so the session cookies
+// and its hidden fields, such as CSRF tokens, are sent back with the credentials.
+type LoginOptions struct {
+	URL       string
+	UserField string
+	PassField string
+	User      string
+	Pass      string
+
+	// SuccessMarker is a text that must be found in the page shown after logging in.
+	// If it is empty any response without an error status code is a successful login.
+	SuccessMarker string
+
+	// LoggedOutMarker is a text found in the responses when the session is lost,
+	// either in the body or in the redirect location. Empty to disable the relogin.
+	LoggedOutMarker string
+}
+
+// Login submits the login form. The http client must have a cookie jar to keep the session
+// for the following requests.
+func (c *BasicClient) Login(ctx context.Context) error {
+	c.loginLock.Lock()
+	defer c.loginLock.Unlock()
+
+	return c.login(ctx)
+}
+
+// relogin logs in again unless another request already did it since the login
+// generation gen was read.
+func (c *BasicClient) relogin(ctx context.Context, gen int) error {
+	c.loginLock.Lock()
+	defer c.loginLock.Unlock()
+
+	if gen != c.loginGen {
+		return nil
+	}
+
+	return c.login(ctx)
+}
+
+// loginGeneration returns the number of successful logins.
+func (c *BasicClient) loginGeneration() int {
+	c.loginLock.Lock()
+	defer c.loginLock.Unlock()
+
+	return c.loginGen
+}
+
+func (c *BasicClient) login(ctx context.Context) error {
+	o := c.Options.Login
+	if o == nil {
+		return nil
+	}
+
+	cr, err := c.send(ctx, http.MethodGet, o.URL, nil)
+	if err != nil {
+		return err
+	}
+
+	doc, err := htmlquery.Parse(cr.Response.Body)
+	cr.Response.Body.Close()
+	if err != nil {
+		return err
+	}
+
+	action, values, err := loginForm(doc, cr.Response.Request.URL, o.PassField)
+	if err != nil {
+		return err
+	}
+
+	values.Set(o.UserField, o.User)
+	values.Set(o.PassField, o.Pass)
+
+	cr, err = c.send(ctx, http.MethodPost, action.String(), strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < maxLoginRedirects && isRedirect(cr.Response.StatusCode); i++ {
+		cr.Response.Body.Close()
+
+		location, err := cr.Response.Location()
+		if err != nil {
+			return err
+		}
+
+		cr, err = c.send(ctx, http.MethodGet, location.String(), nil)
+		if err != nil {
+			return err
+		}
+	}
+	defer cr.Response.Body.Close()
+
+	if cr.Response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%w: status code %d", ErrLoginFailed, cr.Response.StatusCode)
+	}
+
+	if o.SuccessMarker != "" {
+		body, err := io.ReadAll(cr.Response.Body)
+		if err != nil {
+			return err
+		}
+
+		if !strings.Contains(string(body), o.SuccessMarker) {
+			return fmt.Errorf("%w: success marker not found", ErrLoginFailed)
+		}
+	}
+
+	c.loginGen++
+
+	return nil
+}
+
+// isLoggedOut returns true if the response contains the logged out marker. The response
+// body is buffered so it can still be read by the caller.
+func (c *BasicClient) isLoggedOut(cr *ClientResponse) bool {
+	o := c.Options.Login
+	if o == nil || o.LoggedOutMarker == "" || cr.Response.Request == nil {
+		return false
+	}
+
+	if cr.Response.Request.Method != http.MethodGet || !hasDomain(c.Options.CustomDomains, cr.Response.Request.URL.Host) {
+		return false
+	}
+
+	if strings.Contains(cr.Response.Header.Get("Location"), o.LoggedOutMarker) {
+		return true
+	}
+
+	body, err := io.ReadAll(cr.Response.Body)
+	cr.Response.Body.Close()
+	cr.Response.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(string(body), o.LoggedOutMarker)
+}
+
+// loginForm looks for the form with the password field and returns its absolute action URL
+// and the values of its hidden inputs.
+func loginForm(doc *html.Node, base *url.URL, passField string) (*url.URL, url.Values, error) {
+	for _, form := range htmlquery.Find(doc, "//form") {
+		inputs := htmlquery.Find(form, ".//input")
+
+		found := false
+		for _, input := range inputs {
+			if htmlquery.SelectAttr(input, "name") == passField {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			continue
+		}
+
+		action, err := base.Parse(htmlquery.SelectAttr(form, "action"))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		values := url.Values{}
+		for _, input := range inputs {
+			name := htmlquery.SelectAttr(input, "name")
+			if name != "" && strings.EqualFold(htmlquery.SelectAttr(input, "type"), "hidden") {
+				values.Set(name, htmlquery.SelectAttr(input, "value"))
+			}
+		}
+
+		return action, values, nil
+	}
+
+	return nil, nil, fmt.Errorf("%w: login form not found", ErrLoginFailed)
+}
+
+// isRedirect returns true if the status code is a redirect with a location.
+func isRedirect(code int) bool {
+	return code >= http.StatusMultipleChoices && code < http.StatusBadRequest && code != http.StatusNotModified
+}
@@ -0,0 +1,157 @@
+package crawler_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// newLoginServer returns a test server with a login form protected by a CSRF token
+// and a members page that requires the session cookie.
+func newLoginServer(t *testing.T, sessions *int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "csrf", Value: "token"})
+		io.WriteString(w, `<form action="/session" method="post">
+			<input type="hidden" name="csrf" value="token">
+			<input type="text" name="user"><input type="password" name="pass">
+		</form>`)
+	})
+	mux.HandleFunc("POST /session", func(w http.ResponseWriter, r *http.Request) {
+		csrf, err := r.Cookie("csrf")
+		if err != nil || csrf.Value != r.FormValue("csrf") || r.FormValue("user") != "admin" || r.FormValue("pass") != "secret" {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+
+		*sessions++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "valid"})
+		http.Redirect(w, r, "/members", http.StatusFound)
+	})
+	mux.HandleFunc("GET /members", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := r.Cookie("session"); err != nil {
+			io.WriteString(w, "Please log in")
+			return
+		}
+
+		io.WriteString(w, "Welcome back")
+	})
+	mux.HandleFunc("GET /logout", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", MaxAge: -1})
+		io.WriteString(w, "Bye")
+	})
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+func newLoginClient(t *testing.T, ts *httptest.Server, o *crawler.LoginOptions) *crawler.BasicClient {
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	httpClient := &http.Client{
+		Jar: jar,
+		CheckRedirect: func(r *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return crawler.NewBasicClient(&crawler.ClientOptions{
+		CustomDomains: []string{strings.TrimPrefix(ts.URL, "http://")},
+		Login:         o,
+	}, httpClient)
+}
+
+func body(t *testing.T, c *crawler.BasicClient, u string) string {
+	cr, err := c.Get(context.Background(), u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cr.Response.Body.Close()
+
+	b, err := io.ReadAll(cr.Response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
+
+// Test the login form is submitted with its hidden fields and the session is kept.
+func TestLogin(t *testing.T) {
+	sessions := 0
+	ts := newLoginServer(t, &sessions)
+	client := newLoginClient(t, ts, &crawler.LoginOptions{
+		URL:           ts.URL + "/login",
+		UserField:     "user",
+		PassField:     "pass",
+		User:          "admin",
+		Pass:          "secret",
+		SuccessMarker: "Welcome",
+	})
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+
+	if got := body(t, client, ts.URL+"/members"); got != "Welcome back" {
+		t.Errorf("members page: want 'Welcome back' got '%s'", got)
+	}
+}
+
+// Test the login fails if the success marker is not found.
+func TestLoginFailed(t *testing.T) {
+	sessions := 0
+	ts := newLoginServer(t, &sessions)
+	client := newLoginClient(t, ts, &crawler.LoginOptions{
+		URL:           ts.URL + "/login",
+		UserField:     "user",
+		PassField:     "pass",
+		User:          "admin",
+		Pass:          "wrong",
+		SuccessMarker: "Welcome",
+	})
+
+	if err := client.Login(context.Background()); !errors.Is(err, crawler.ErrLoginFailed) {
+		t.Errorf("Login() want ErrLoginFailed got %v", err)
+	}
+}
+
+// Test the client logs in again when the logged out marker is found.
+func TestRelogin(t *testing.T) {
+	sessions := 0
+	ts := newLoginServer(t, &sessions)
+	client := newLoginClient(t, ts, &crawler.LoginOptions{
+		URL:             ts.URL + "/login",
+		UserField:       "user",
+		PassField:       "pass",
+		User:            "admin",
+		Pass:            "secret",
+		LoggedOutMarker: "Please log in",
+	})
+
+	if err := client.Login(context.Background()); err != nil {
+		t.Fatalf("Login() error: %v", err)
+	}
+
+	body(t, client, ts.URL+"/logout")
+
+	if got := body(t, client, ts.URL+"/members"); got != "Welcome back" {
+		t.Errorf("members page after relogin: want 'Welcome back' got '%s'", got)
+	}
+
+	if sessions != 2 {
+		t.Errorf("want 2 logins got %d", sessions)
+	}
+}
@@ -1,22 +1,28 @@
 package crawler
 
+import "context"
+
 type Queue struct {
-	in     chan *RequestMessage
-	out    chan *RequestMessage
-	ack    chan string
-	count  chan int
-	active chan bool
-	done   chan struct{}
+	in       chan *RequestMessage
+	out      chan *RequestMessage
+	ack      chan string
+	retry    chan *RequestMessage
+	count    chan int
+	active   chan bool
+	snapshot chan chan []*RequestMessage
+	done     chan struct{}
 }
 
 func NewQueue() *Queue {
 	q := Queue{
-		in:     make(chan *RequestMessage),
-		out:    make(chan *RequestMessage),
-		ack:    make(chan string),
-		count:  make(chan int),
-		active: make(chan bool),
-		done:   make(chan struct{}),
+		in:       make(chan *RequestMessage),
+		out:      make(chan *RequestMessage),
+		ack:      make(chan string),
+		retry:    make(chan *RequestMessage),
+		count:    make(chan int),
+		active:   make(chan bool),
+		snapshot: make(chan chan []*RequestMessage),
+		done:     make(chan struct{}),
 	}
 
 	go q.manage()
@@ -32,11 +38,12 @@ func (q *Queue) manage() {
 		close(q.ack)
 		close(q.count)
 		close(q.active)
+		close(q.snapshot)
 		close(q.done)
 	}()
 
 	queue := []*RequestMessage{}
-	active := make(map[string]bool)
+	active := make(map[string]*RequestMessage)
 
 	var first *RequestMessage
 	var out chan *RequestMessage
@@ -44,7 +51,7 @@ func (q *Queue) manage() {
 	for {
 		if first == nil && len(queue) > 0 {
 			first = queue[0]
-			active[first.URL.String()] = true
+			active[first.URL.String()] = first
 			queue = queue[1:]
 		}
 
@@ -65,10 +72,33 @@ func (q *Queue) manage() {
 			first = nil
 		case v := <-q.ack:
 			delete(active, v)
+		case v := <-q.retry:
+			delete(active, v.URL.String())
+			queue = append(queue, v)
+		case r := <-q.snapshot:
+			r <- pending(active, first, queue)
 		}
 	}
 }
 
+// pending returns a slice with the active elements followed by the elements that are
+// still waiting in the queue. Active elements have not been acknowledged yet, so they
+// are considered pending as well.
+func pending(active map[string]*RequestMessage, first *RequestMessage, queue []*RequestMessage) []*RequestMessage {
+	p := make([]*RequestMessage, 0, len(active)+len(queue))
+	for _, v := range active {
+		if v != first {
+			p = append(p, v)
+		}
+	}
+
+	if first != nil {
+		p = append(p, first)
+	}
+
+	return append(p, queue...)
+}
+
 // Adds a new value to the queue's end.
 func (q *Queue) Push(value *RequestMessage) {
 	q.in <- value
@@ -84,6 +114,16 @@ func (q *Queue) Ack(s string) {
 	q.ack <- s
 }
 
+// Retry acknowledges a message and adds it again to the queue's end. Both things happen
+// at once so the queue doesn't look empty in between. Retry may be called by the crawler's
+// consumers while the queue is being stopped, so it gives up once the context is cancelled.
+func (q *Queue) Retry(ctx context.Context, value *RequestMessage) {
+	select {
+	case q.retry <- value:
+	case <-ctx.Done():
+	}
+}
+
 // Returns the number of items currently in the queue.
 func (q *Queue) Count() int {
 	v, ok := <-q.count
@@ -99,6 +139,15 @@ func (q *Queue) Active() bool {
 	return <-q.active
 }
 
+// Snapshot returns a copy of all the elements in the queue, including the ones that
+// have been polled but not acknowledged yet.
+func (q *Queue) Snapshot() []*RequestMessage {
+	r := make(chan []*RequestMessage)
+	q.snapshot <- r
+
+	return <-r
+}
+
 // Done stops the queue and closes all of its channels.
 func (q *Queue) Done() {
 	q.done <- struct{}{}
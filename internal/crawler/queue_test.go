@@ -72,3 +72,36 @@ func TestActiveNotActive(t *testing.T) {
 
 	queue.Done()
 }
+
+func TestSnapshot(t *testing.T) {
+	queue := crawler.NewQueue()
+
+	elements := []*crawler.RequestMessage{}
+	for _, p := range []string{"element1", "element2", "element3"} {
+		u := &url.URL{Scheme: "https", Host: "example.com", Path: p}
+		e := &crawler.RequestMessage{URL: u}
+		elements = append(elements, e)
+		queue.Push(e)
+	}
+
+	// Polled but not acknowledged elements must be part of the snapshot.
+	p1 := queue.Poll()
+	snapshot := queue.Snapshot()
+	if len(snapshot) != len(elements) {
+		t.Errorf("snapshot length %d != %d", len(snapshot), len(elements))
+	}
+
+	queue.Ack(p1.URL.String())
+	snapshot = queue.Snapshot()
+	if len(snapshot) != len(elements)-1 {
+		t.Errorf("snapshot length %d != %d", len(snapshot), len(elements)-1)
+	}
+
+	for _, e := range snapshot {
+		if e == p1 {
+			t.Errorf("acknowledged element %v should not be in the snapshot", e)
+		}
+	}
+
+	queue.Done()
+}
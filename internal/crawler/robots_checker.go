@@ -1,9 +1,11 @@
 package crawler
 
 import (
+	"context"
 	"errors"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/temoto/robotstxt"
 )
@@ -23,8 +25,8 @@ func NewRobotsChecker(client Client) *RobotsChecker {
 }
 
 // Returns true if the URL is blocked by robots.txt
-func (r *RobotsChecker) IsBlocked(u *url.URL) bool {
-	robot, err := r.getRobotsMap(u)
+func (r *RobotsChecker) IsBlocked(ctx context.Context, u *url.URL) bool {
+	robot, err := r.getRobotsMap(ctx, u)
 	if err != nil || robot == nil {
 		return false
 	}
@@ -38,8 +40,8 @@ func (r *RobotsChecker) IsBlocked(u *url.URL) bool {
 }
 
 // Returns true if the robots.txt file exists and is valid
-func (r *RobotsChecker) Exists(u *url.URL) bool {
-	robot, err := r.getRobotsMap(u)
+func (r *RobotsChecker) Exists(ctx context.Context, u *url.URL) bool {
+	robot, err := r.getRobotsMap(ctx, u)
 	if err != nil {
 		return false
 	}
@@ -52,8 +54,8 @@ func (r *RobotsChecker) Exists(u *url.URL) bool {
 }
 
 // Returns a list of sitemaps found in the robots.txt file
-func (r *RobotsChecker) GetSitemaps(u *url.URL) []string {
-	robot, err := r.getRobotsMap(u)
+func (r *RobotsChecker) GetSitemaps(ctx context.Context, u *url.URL) []string {
+	robot, err := r.getRobotsMap(ctx, u)
 	if err != nil || robot == nil {
 		return []string{}
 	}
@@ -61,8 +63,23 @@ func (r *RobotsChecker) GetSitemaps(u *url.URL) []string {
 	return robot.Sitemaps
 }
 
+// Returns the Crawl-delay directive for the client's user agent, or zero if it is not set.
+func (r *RobotsChecker) GetCrawlDelay(ctx context.Context, u *url.URL) time.Duration {
+	robot, err := r.getRobotsMap(ctx, u)
+	if err != nil || robot == nil {
+		return 0
+	}
+
+	group := robot.FindGroup(r.client.GetUA())
+	if group == nil {
+		return 0
+	}
+
+	return group.CrawlDelay
+}
+
 // Returns a RobotsData checking if it has already been created and stored in the robotsMap
-func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error) {
+func (r *RobotsChecker) getRobotsMap(ctx context.Context, u *url.URL) (*robotstxt.RobotsData, error) {
 	r.rlock.Lock()
 	defer r.rlock.Unlock()
 
@@ -71,7 +88,7 @@ func (r *RobotsChecker) getRobotsMap(u *url.URL) (*robotstxt.RobotsData, error)
 		return robot, nil
 	}
 
-	resp, err := r.client.Get(u.Scheme + "://" + u.Host + "/robots.txt")
+	resp, err := r.client.Get(ctx, u.Scheme+"://"+u.Host+"/robots.txt")
 	if err != nil {
 		r.robotsMap[u.Host] = nil
 		return nil, err
@@ -2,26 +2,29 @@ package crawler_test
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/crawler"
 )
 
 type MockClient struct{}
 
-func (t *MockClient) Head(u string) (*crawler.ClientResponse, error) {
+func (t *MockClient) Head(ctx context.Context, u string) (*crawler.ClientResponse, error) {
 	return &crawler.ClientResponse{}, nil
 }
-func (t *MockClient) Get(u string) (*crawler.ClientResponse, error) {
+func (t *MockClient) Get(ctx context.Context, u string) (*crawler.ClientResponse, error) {
 	r := &http.Response{}
 	if strings.HasPrefix(u, "https://example.com/") {
 		body := `
 		User-Agent: *
 		Disallow: /disallowed
+		Crawl-delay: 3
 		Sitemap: /sitemap.xml
 		`
 		r.Body = io.NopCloser(bytes.NewBufferString(body))
@@ -46,7 +49,7 @@ func TestIsBlocked(t *testing.T) {
 		t.Errorf("url parse error %v", err)
 	}
 
-	if !robotsChecker.IsBlocked(u) {
+	if !robotsChecker.IsBlocked(context.Background(), u) {
 		t.Errorf("Url %s should be blocked", u.String())
 	}
 
@@ -55,7 +58,7 @@ func TestIsBlocked(t *testing.T) {
 		t.Errorf("url parse error %v", err)
 	}
 
-	if robotsChecker.IsBlocked(u) {
+	if robotsChecker.IsBlocked(context.Background(), u) {
 		t.Errorf("url %s should not be blocked", u.String())
 	}
 }
@@ -68,7 +71,7 @@ func TestRobotsExist(t *testing.T) {
 		t.Errorf("url parse error %v", err)
 	}
 
-	if robotsChecker.Exists(u) {
+	if robotsChecker.Exists(context.Background(), u) {
 		t.Errorf("robots.txt should not exist in %s", u.String())
 	}
 
@@ -77,7 +80,7 @@ func TestRobotsExist(t *testing.T) {
 		t.Errorf("url parse error %v", err)
 	}
 
-	if !robotsChecker.Exists(u) {
+	if !robotsChecker.Exists(context.Background(), u) {
 		t.Errorf("robots.txt should exist in %s", u.String())
 	}
 }
@@ -90,7 +93,7 @@ func TestGetSitemap(t *testing.T) {
 		t.Errorf("url parse error %v", err)
 	}
 
-	sitemaps := robotsChecker.GetSitemaps(u)
+	sitemaps := robotsChecker.GetSitemaps(context.Background(), u)
 	if len(sitemaps) != 1 || sitemaps[0] != "/sitemap.xml" {
 		t.Errorf("error getting sitemap from robots.txt in %s", u.String())
 	}
@@ -100,8 +103,30 @@ func TestGetSitemap(t *testing.T) {
 		t.Errorf("url parse error %v", err)
 	}
 
-	sitemaps = robotsChecker.GetSitemaps(u)
+	sitemaps = robotsChecker.GetSitemaps(context.Background(), u)
 	if len(sitemaps) > 0 {
 		t.Errorf("error getting sitemap from robots.txt in %s", u.String())
 	}
 }
+
+// TestGetCrawlDelay tests the Crawl-delay directive of the robots.txt file.
+func TestGetCrawlDelay(t *testing.T) {
+	robotsChecker := crawler.NewRobotsChecker(&MockClient{})
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if d := robotsChecker.GetCrawlDelay(context.Background(), u); d != 3*time.Second {
+		t.Errorf("crawl delay %v in %s, expected 3s", d, u.String())
+	}
+
+	u, err = url.Parse("https://norobots.com/")
+	if err != nil {
+		t.Errorf("url parse error %v", err)
+	}
+
+	if d := robotsChecker.GetCrawlDelay(context.Background(), u); d != 0 {
+		t.Errorf("crawl delay %v in %s, expected 0", d, u.String())
+	}
+}
@@ -0,0 +1,94 @@
+package crawler
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Scope restricts the URLs the crawler will request using include and exclude rules.
+// Rules starting with "/" are path prefixes matched against the URL's path and query,
+// any other rule is a regular expression matched against the full URL.
+type Scope struct {
+	include []scopeRule
+	exclude []scopeRule
+}
+
+type scopeRule struct {
+	prefix string
+	re     *regexp.Regexp
+}
+
+// NewScope returns a new Scope with the include and exclude rules. It returns an error
+// if any of the rules is not a valid regular expression.
+func NewScope(include, exclude []string) (*Scope, error) {
+	var err error
+	s := &Scope{}
+
+	s.include, err = parseScopeRules(include)
+	if err != nil {
+		return nil, err
+	}
+
+	s.exclude, err = parseScopeRules(exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// InScope returns true if the URL matches any of the include rules, or if there are
+// no include rules, and it doesn't match any of the exclude rules.
+// A nil Scope allows all URLs.
+func (s *Scope) InScope(u *url.URL) bool {
+	if s == nil {
+		return true
+	}
+
+	if len(s.include) > 0 && !matchScopeRules(s.include, u) {
+		return false
+	}
+
+	return !matchScopeRules(s.exclude, u)
+}
+
+// parseScopeRules compiles the rules ignoring the empty ones.
+func parseScopeRules(rules []string) ([]scopeRule, error) {
+	parsed := []scopeRule{}
+	for _, r := range rules {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+
+		if strings.HasPrefix(r, "/") {
+			parsed = append(parsed, scopeRule{prefix: r})
+			continue
+		}
+
+		re, err := regexp.Compile(r)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed = append(parsed, scopeRule{re: re})
+	}
+
+	return parsed, nil
+}
+
+// matchScopeRules returns true if the URL matches any of the rules.
+func matchScopeRules(rules []scopeRule, u *url.URL) bool {
+	for _, r := range rules {
+		if r.re != nil && r.re.MatchString(u.String()) {
+			return true
+		}
+
+		if r.re == nil && strings.HasPrefix(u.RequestURI(), r.prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,51 @@
+package crawler_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/crawler"
+)
+
+// TestScope tests the include and exclude rules using path prefixes and regular expressions.
+func TestScope(t *testing.T) {
+	scope, err := crawler.NewScope(
+		[]string{"/blog/", `^https://example\.com/docs/v[0-9]+/`},
+		[]string{"/blog/search?", `[?&]sort=`, ""},
+	)
+	if err != nil {
+		t.Fatalf("NewScope error %v", err)
+	}
+
+	table := []struct {
+		url  string
+		want bool
+	}{
+		{"https://example.com/blog/", true},
+		{"https://example.com/blog/post-1", true},
+		{"https://example.com/blog/post-1?page=2&sort=date", false},
+		{"https://example.com/blog/search?q=test", false},
+		{"https://example.com/docs/v2/install", true},
+		{"https://example.com/docs/latest/install", false},
+		{"https://example.com/about", false},
+	}
+
+	for _, tc := range table {
+		u, err := url.Parse(tc.url)
+		if err != nil {
+			t.Fatalf("url parse error %v", err)
+		}
+
+		if got := scope.InScope(u); got != tc.want {
+			t.Errorf("%s: InScope %v, expected %v", tc.url, got, tc.want)
+		}
+	}
+}
+
+// TestScopeNotValid tests that NewScope returns an error if a rule is not a valid regular expression.
+func TestScopeNotValid(t *testing.T) {
+	_, err := crawler.NewScope([]string{"[a-z"}, nil)
+	if err == nil {
+		t.Error("NewScope should return an error with a non valid regular expression")
+	}
+}
@@ -1,6 +1,7 @@
 package crawler
 
 import (
+	"context"
 	"errors"
 	"sync"
 
@@ -20,9 +21,9 @@ func NewSitemapChecker(client Client, limit int) *SitemapChecker {
 }
 
 // Check if any of the sitemap URLs provided exist
-func (sc *SitemapChecker) SitemapExists(URLs []string) bool {
+func (sc *SitemapChecker) SitemapExists(ctx context.Context, URLs []string) bool {
 	for _, s := range URLs {
-		if sc.urlExists(s) {
+		if sc.urlExists(ctx, s) {
 			return true
 		}
 	}
@@ -31,8 +32,8 @@ func (sc *SitemapChecker) SitemapExists(URLs []string) bool {
 }
 
 // Check if a URL exists by checking its status code
-func (sc *SitemapChecker) urlExists(URL string) bool {
-	resp, err := sc.client.Head(URL)
+func (sc *SitemapChecker) urlExists(ctx context.Context, URL string) bool {
+	resp, err := sc.client.Head(ctx, URL)
 	if err != nil {
 		return false
 	}
@@ -42,7 +43,7 @@ func (sc *SitemapChecker) urlExists(URL string) bool {
 
 // Parse the sitemaps using a callback function on each entry
 // For each URL provided check if it's an index sitemap
-func (sc *SitemapChecker) ParseSitemaps(URLs []string, callback func(u string)) {
+func (sc *SitemapChecker) ParseSitemaps(ctx context.Context, URLs []string, callback func(u string)) {
 	c := 0
 	wg := new(sync.WaitGroup)
 	lock := sync.RWMutex{}
@@ -55,7 +56,9 @@ func (sc *SitemapChecker) ParseSitemaps(URLs []string, callback func(u string))
 			// Each sitemap is parsed in its own Go routine
 			// If the sitemap limit is hit the parser function returns an error to stop the process
 			go func(s string) {
-				resp, err := sc.client.Get(s)
+				defer wg.Done()
+
+				resp, err := sc.client.Get(ctx, s)
 				if err != nil {
 					return
 				}
@@ -74,8 +77,6 @@ func (sc *SitemapChecker) ParseSitemaps(URLs []string, callback func(u string))
 
 					return nil
 				})
-
-				wg.Done()
 			}(s)
 		}
 	}
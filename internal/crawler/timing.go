@@ -0,0 +1,107 @@
+package crawler
+
+import (
+	"crypto/tls"
+	"math"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timing holds the duration in milliseconds of each phase of a request. The DNS lookup,
+// TCP connect and TLS handshake are zero if the request reused an open connection.
+type Timing struct {
+	DNSLookup    int
+	TCPConnect   int
+	TLSHandshake int
+	TTFB         int
+	Download     int
+	Protocol     string
+	TLSVersion   string
+}
+
+// timer records the start and end of the request phases using an httptrace.ClientTrace.
+// The trace callbacks can be called from different goroutines, so they are guarded by a lock.
+type timer struct {
+	lock *sync.Mutex
+
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	downloadDone time.Time
+}
+
+func newTimer() *timer {
+	return &timer{lock: &sync.Mutex{}, start: time.Now()}
+}
+
+// trace returns the ClientTrace that records the time of each request phase.
+func (t *timer) trace() *httptrace.ClientTrace {
+	set := func(v *time.Time, keepFirst bool) {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		if keepFirst && !v.IsZero() {
+			return
+		}
+		*v = time.Now()
+	}
+
+	// With multiple addresses the connection may be attempted more than once, the connect
+	// phase goes from the first attempt to the last one that finishes.
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { set(&t.dnsStart, true) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { set(&t.dnsDone, false) },
+		ConnectStart:         func(string, string) { set(&t.connectStart, true) },
+		ConnectDone:          func(string, string, error) { set(&t.connectDone, false) },
+		TLSHandshakeStart:    func() { set(&t.tlsStart, true) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { set(&t.tlsDone, false) },
+		GotFirstResponseByte: func() { set(&t.firstByte, false) },
+	}
+}
+
+// done marks the end of the body download.
+func (t *timer) done() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.downloadDone = time.Now()
+}
+
+// timing returns the duration of each phase along with the protocol and TLS version
+// of the response.
+func (t *timer) timing(resp *http.Response) Timing {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	timing := Timing{
+		DNSLookup:    milliseconds(t.dnsStart, t.dnsDone),
+		TCPConnect:   milliseconds(t.connectStart, t.connectDone),
+		TLSHandshake: milliseconds(t.tlsStart, t.tlsDone),
+		TTFB:         milliseconds(t.start, t.firstByte),
+		Download:     milliseconds(t.firstByte, t.downloadDone),
+	}
+
+	if resp != nil {
+		timing.Protocol = resp.Proto
+		if resp.TLS != nil {
+			timing.TLSVersion = tls.VersionName(resp.TLS.Version)
+		}
+	}
+
+	return timing
+}
+
+// milliseconds returns the number of milliseconds between start and end rounded up,
+// or zero if any of them is not set.
+func milliseconds(start, end time.Time) int {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+
+	return int(math.Ceil(float64(end.Sub(start)) / float64(time.Millisecond)))
+}
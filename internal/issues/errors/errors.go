@@ -88,4 +88,30 @@ const (
 	ErrorDOMSize                                 // HTML documents with excessive DOM size
 	ErrorPaginationLink                          // Pages with next and prev attributes missing the actual link
 	ErrorLocalhostLinks                          // Pages with links to localhost or 127.0.0.1
+	ErrorInternalTemporaryRedirect               // Pages with internal links to temporary redirects
+	ErrorRedirectChainError                      // Redirect chains ending in a 4xx or 5xx response
+	ErrorCertificateExpiring                     // Pages served with a TLS certificate that is about to expire
+	ErrorCertificateHostnameMismatch             // Pages served with a TLS certificate not valid for the host
+	ErrorWeakTLSVersion                          // Pages served over TLS 1.1 or older
+	ErrorMixedCertificates                       // Hosts with different certificate setups in www and the apex domain
+	ErrorActiveMixedContent                      // HTTPS pages loading scripts, styles or iframes over http
+	ErrorPassiveMixedContent                     // HTTPS pages loading images, audios or videos over http
+	ErrorMissingReferrerPolicy                   // Pages without a Referrer-Policy
+	ErrorMissingPermissionsPolicy                // Pages without a Permissions-Policy header
+	ErrorMissingFrameOptions                     // Pages without X-Frame-Options or a CSP frame-ancestors directive
+	ErrorUnsafeCSP                               // Pages with a CSP allowing unsafe-inline or wildcard sources
+	ErrorCookieWithoutSecure                     // HTTPS pages setting cookies without the Secure attribute
+	ErrorCookieWithoutHttpOnly                   // Pages setting cookies without the HttpOnly attribute
+	ErrorCookieWithoutSameSite                   // Pages setting cookies without the SameSite attribute
+	ErrorMissingSRI                              // Pages loading cross-origin scripts or styles without integrity attribute
+	ErrorInvalidJSONLD                           // Pages with JSON-LD scripts that can't be parsed
+	ErrorStructuredDataProperties                // Pages with structured data items missing required properties
+	ErrorStructuredDataConflict                  // Duplicate pages with different structured data types
+	ErrorMissingOGTitle                          // Pages without the og:title tag
+	ErrorMissingOGImage                          // Pages without the og:image tag
+	ErrorOGURLCanonicalMismatch                  // Pages with an og:url different from the canonical URL
+	ErrorOGImageRelative                         // Pages with a relative og:image URL
+	ErrorOGImageBroken                           // Pages with an og:image URL that returns an error
+	ErrorDuplicatedOGTitle                       // Pages with duplicated og:title
+	ErrorNearDuplicateContent                    // Pages with a main text similar to other pages
 )
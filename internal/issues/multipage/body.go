@@ -24,3 +24,17 @@ func (sr *SqlReporter) DuplicatedContent(c *models.Crawl) *models.MultipageIssue
 		ErrorType: errors.ErrorDuplicatedContent,
 	}
 }
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages with
+// a main text similar to other pages. The near-duplicates are found once the crawl ends.
+func (sr *SqlReporter) NearDuplicateContentReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT pagereport_id
+		FROM near_duplicates
+		WHERE crawl_id = ?`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorNearDuplicateContent,
+	}
+}
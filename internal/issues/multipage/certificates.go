@@ -0,0 +1,25 @@
+package multipage
+
+import (
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for hosts
+// that have a different TLS certificate setup in the www subdomain and the apex domain,
+// either because only one of them has a valid certificate or because they are issued by
+// different authorities. The issue is reported in the first page crawled in each host.
+func (sr *SqlReporter) MixedCertificatesReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT a.pagereport_id
+		FROM certificates AS a
+		INNER JOIN certificates AS b ON b.crawl_id = a.crawl_id
+			AND (b.host = CONCAT("www.", a.host) OR a.host = CONCAT("www.", b.host))
+		WHERE a.crawl_id = ? AND (a.valid != b.valid OR a.issuer != b.issuer)`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorMixedCertificates,
+	}
+}
@@ -52,3 +52,19 @@ func (sr *SqlReporter) FollowNoFollowReporter(c *models.Crawl) *models.Multipage
 		ErrorType: errors.ErrorIncomingFollowNofollow,
 	}
 }
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// with internal links to URLs that respond with a temporary redirect.
+func (sr *SqlReporter) TemporaryRedirectLinksReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT links.pagereport_id
+		FROM links
+		INNER JOIN pagereports ON pagereports.url_hash = links.url_hash AND pagereports.crawl_id = links.crawl_id
+		WHERE links.crawl_id = ? AND pagereports.crawled = 1 AND pagereports.status_code IN (302, 303, 307)`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorInternalTemporaryRedirect,
+	}
+}
@@ -23,10 +23,22 @@ func (sr *SqlReporter) GetAllReporters() []models.MultipageCallback {
 	return []models.MultipageCallback{
 		// Add content issue reporters
 		sr.DuplicatedContent,
+		sr.NearDuplicateContentReporter,
 
 		// Add status code issue reporters
 		sr.RedirectChainsReporter,
 		sr.RedirectLoopsReporter,
+		sr.RedirectChainErrorReporter,
+
+		// Add certificate issue reporters
+		sr.MixedCertificatesReporter,
+
+		// Add structured data issue reporters
+		sr.StructuredDataConflictReporter,
+
+		// Add social tags issue reporters
+		sr.BrokenOGImageReporter,
+		sr.DuplicatedOGTitleReporter,
 
 		// Add title issue reporters
 		sr.DuplicatedTitleReporter,
@@ -38,6 +50,7 @@ func (sr *SqlReporter) GetAllReporters() []models.MultipageCallback {
 		sr.OrphanPagesReporter,
 		sr.NoFollowIndexableReporter,
 		sr.FollowNoFollowReporter,
+		sr.TemporaryRedirectLinksReporter,
 
 		// Add hreflang reporters
 		sr.MissingHrelangReturnLinks,
@@ -0,0 +1,51 @@
+package multipage
+
+import (
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages
+// with an og:image that was crawled and responded with a 4xx or 5xx status code.
+func (sr *SqlReporter) BrokenOGImageReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT social_tags.pagereport_id
+		FROM social_tags
+		INNER JOIN pagereports ON pagereports.url_hash = social_tags.url_hash AND pagereports.crawl_id = social_tags.crawl_id
+		WHERE social_tags.crawl_id = ? AND social_tags.property = "og:image" AND pagereports.status_code >= 400`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorOGImageBroken,
+	}
+}
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for pages with
+// identical og:title tags. Like the duplicated titles, it only considers canonical html pages.
+func (sr *SqlReporter) DuplicatedOGTitleReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT social_tags.pagereport_id
+		FROM social_tags
+		INNER JOIN pagereports ON pagereports.id = social_tags.pagereport_id
+		INNER JOIN (
+			SELECT
+				social_tags.content
+			FROM social_tags
+			INNER JOIN pagereports ON pagereports.id = social_tags.pagereport_id
+			WHERE social_tags.crawl_id = ? AND social_tags.property = "og:title" AND social_tags.content <> ""
+			AND pagereports.status_code >= 200 AND pagereports.status_code < 300
+			AND (pagereports.canonical = "" OR pagereports.canonical = pagereports.url) AND pagereports.crawled = 1
+			GROUP BY social_tags.content
+			HAVING COUNT(DISTINCT social_tags.pagereport_id) > 1
+		) AS d ON d.content = social_tags.content
+		WHERE social_tags.crawl_id = ? AND social_tags.property = "og:title"
+		AND pagereports.status_code >= 200 AND pagereports.status_code < 300
+		AND (pagereports.canonical = "" OR pagereports.canonical = pagereports.url) AND pagereports.crawled = 1`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id, c.Id),
+		ErrorType: errors.ErrorDuplicatedOGTitle,
+	}
+}
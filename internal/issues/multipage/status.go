@@ -38,3 +38,18 @@ func (sr *SqlReporter) RedirectLoopsReporter(c *models.Crawl) *models.MultipageI
 		ErrorType: errors.ErrorRedirectLoop,
 	}
 }
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for redirect
+// chains that end in a 4xx or 5xx response. The issue is reported in the chain's first page.
+func (sr *SqlReporter) RedirectChainErrorReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	query := `
+		SELECT
+			DISTINCT pagereport_id
+		FROM redirect_hops
+		WHERE crawl_id = ? AND status_code >= 400`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id),
+		ErrorType: errors.ErrorRedirectChainError,
+	}
+}
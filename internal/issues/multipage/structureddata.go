@@ -0,0 +1,40 @@
+package multipage
+
+import (
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Creates a MultipageIssueReporter object that contains the SQL query to check for duplicate
+// pages, those canonicalized to the same URL, that have different structured data types.
+// All the pages in the group are reported, including the canonical page.
+func (sr *SqlReporter) StructuredDataConflictReporter(c *models.Crawl) *models.MultipageIssueReporter {
+	types := `
+		SELECT
+			pagereports.id,
+			IF(pagereports.canonical = "", pagereports.url, pagereports.canonical) AS canonical_url,
+			COALESCE((
+				SELECT GROUP_CONCAT(DISTINCT structured_data.type ORDER BY structured_data.type)
+				FROM structured_data
+				WHERE structured_data.pagereport_id = pagereports.id AND structured_data.error = ""
+			), "") AS types
+		FROM pagereports
+		WHERE pagereports.crawl_id = ? AND pagereports.media_type = "text/html" AND pagereports.crawled = 1
+		AND pagereports.status_code >= 200 AND pagereports.status_code < 300`
+
+	query := `
+		SELECT
+			p.id
+		FROM (` + types + `) AS p
+		INNER JOIN (
+			SELECT canonical_url
+			FROM (` + types + `) AS t
+			GROUP BY canonical_url
+			HAVING COUNT(DISTINCT types) > 1
+		) AS d ON d.canonical_url = p.canonical_url`
+
+	return &models.MultipageIssueReporter{
+		Pstream:   sr.pageReportsQuery(query, c.Id, c.Id),
+		ErrorType: errors.ErrorStructuredDataConflict,
+	}
+}
@@ -0,0 +1,71 @@
+package page
+
+import (
+	"net/http"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if
+// the page is served over https and sets cookies without the Secure attribute.
+func NewCookieWithoutSecureReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.ParsedURL == nil || pageReport.ParsedURL.Scheme != "https" {
+			return false
+		}
+
+		for _, cookie := range pageReport.Cookies {
+			if !cookie.Secure {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorCookieWithoutSecure,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if
+// the page sets cookies without the HttpOnly attribute, which can be read by scripts.
+func NewCookieWithoutHttpOnlyReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		for _, cookie := range pageReport.Cookies {
+			if !cookie.HttpOnly {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorCookieWithoutHttpOnly,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if
+// the page sets cookies without a valid SameSite attribute.
+func NewCookieWithoutSameSiteReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		for _, cookie := range pageReport.Cookies {
+			if cookie.SameSite == "" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorCookieWithoutSameSite,
+		Callback:  c,
+	}
+}
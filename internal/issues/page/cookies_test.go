@@ -0,0 +1,96 @@
+package page_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/issues/page"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Test the cookie reporters with a page setting a cookie with all the attributes.
+// The reporters should not report any issue.
+func TestCookiesNoIssues(t *testing.T) {
+	parsedURL, err := url.Parse("https://example.com")
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	pageReport := &models.PageReport{
+		ParsedURL: parsedURL,
+		Cookies: []models.Cookie{
+			{Name: "session", Domain: "example.com", Secure: true, HttpOnly: true, SameSite: "Lax"},
+		},
+	}
+
+	reporters := []*models.PageIssueReporter{
+		page.NewCookieWithoutSecureReporter(),
+		page.NewCookieWithoutHttpOnlyReporter(),
+		page.NewCookieWithoutSameSiteReporter(),
+	}
+
+	for _, reporter := range reporters {
+		if reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestCookiesNoIssues: error type %d reportsIssue should be false", reporter.ErrorType)
+		}
+	}
+}
+
+// Test the cookie reporters with a page setting a cookie without any attribute.
+// The reporters should report the issues.
+func TestCookiesIssues(t *testing.T) {
+	parsedURL, err := url.Parse("https://example.com")
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	pageReport := &models.PageReport{
+		ParsedURL: parsedURL,
+		Cookies: []models.Cookie{
+			{Name: "session", Domain: "example.com", Secure: true, HttpOnly: true, SameSite: "Lax"},
+			{Name: "tracking", Domain: "example.com"},
+		},
+	}
+
+	table := []struct {
+		reporter  *models.PageIssueReporter
+		errorType int
+	}{
+		{page.NewCookieWithoutSecureReporter(), errors.ErrorCookieWithoutSecure},
+		{page.NewCookieWithoutHttpOnlyReporter(), errors.ErrorCookieWithoutHttpOnly},
+		{page.NewCookieWithoutSameSiteReporter(), errors.ErrorCookieWithoutSameSite},
+	}
+
+	for _, test := range table {
+		if test.reporter.ErrorType != test.errorType {
+			t.Errorf("TestCookiesIssues: error type is not correct")
+		}
+
+		if !test.reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestCookiesIssues: error type %d reportsIssue should be true", test.errorType)
+		}
+	}
+}
+
+// Test the CookieWithoutSecure reporter with a page served over http.
+// The Secure attribute can't be used so it should not report the issue.
+func TestCookieWithoutSecureHTTPPage(t *testing.T) {
+	parsedURL, err := url.Parse("http://example.com")
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	pageReport := &models.PageReport{
+		ParsedURL: parsedURL,
+		Cookies:   []models.Cookie{{Name: "session", Domain: "example.com"}},
+	}
+
+	reporter := page.NewCookieWithoutSecureReporter()
+	if reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+		t.Errorf("TestCookieWithoutSecureHTTPPage: reportsIssue should be false")
+	}
+}
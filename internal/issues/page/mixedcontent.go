@@ -0,0 +1,62 @@
+package page
+
+import (
+	"net/http"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Returns a report_manager.PageIssueReporter with a callback function that returns true if
+// the page is served over https with a 20x status code and loads scripts, stylesheets or
+// iframes using the http scheme.
+func NewActiveMixedContentReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !pageReport.Crawled {
+			return false
+		}
+
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		if pageReport.StatusCode < 200 || pageReport.StatusCode >= 300 {
+			return false
+		}
+
+		return len(pageReport.ActiveMixedContent()) > 0
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorActiveMixedContent,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that returns true if
+// the page is served over https with a 20x status code and loads images, audios or videos
+// using the http scheme.
+func NewPassiveMixedContentReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !pageReport.Crawled {
+			return false
+		}
+
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		if pageReport.StatusCode < 200 || pageReport.StatusCode >= 300 {
+			return false
+		}
+
+		return len(pageReport.PassiveMixedContent()) > 0
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorPassiveMixedContent,
+		Callback:  c,
+	}
+}
@@ -0,0 +1,122 @@
+package page_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/issues/page"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Test the ActiveMixedContent reporter with https pages loading scripts, styles and iframes.
+// The reporter should only report the issue if one of them uses the http scheme.
+func TestActiveMixedContent(t *testing.T) {
+	pageURL := "https://example.com"
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	reporter := page.NewActiveMixedContentReporter()
+	if reporter.ErrorType != errors.ErrorActiveMixedContent {
+		t.Errorf("TestActiveMixedContent: error type is not correct")
+	}
+
+	table := []struct {
+		pageReport *models.PageReport
+		expected   bool
+	}{
+		{&models.PageReport{Scripts: []string{"https://example.com/app.js"}}, false},
+		{&models.PageReport{Scripts: []string{"http://example.com/app.js"}}, true},
+		{&models.PageReport{Styles: []string{"http://example.com/style.css"}}, true},
+		{&models.PageReport{Iframes: []string{"http://example.com/frame"}}, true},
+		{&models.PageReport{Images: []models.Image{{URL: "http://example.com/img.jpg"}}}, false},
+	}
+
+	for _, test := range table {
+		test.pageReport.Crawled = true
+		test.pageReport.URL = pageURL
+		test.pageReport.ParsedURL = parsedURL
+		test.pageReport.MediaType = "text/html"
+		test.pageReport.StatusCode = 200
+
+		reportsIssue := reporter.Callback(test.pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != test.expected {
+			t.Errorf("TestActiveMixedContent: reportsIssue should be %v", test.expected)
+		}
+	}
+}
+
+// Test the PassiveMixedContent reporter with https pages loading images, audios and videos.
+// The reporter should only report the issue if one of them uses the http scheme.
+func TestPassiveMixedContent(t *testing.T) {
+	pageURL := "https://example.com"
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	reporter := page.NewPassiveMixedContentReporter()
+	if reporter.ErrorType != errors.ErrorPassiveMixedContent {
+		t.Errorf("TestPassiveMixedContent: error type is not correct")
+	}
+
+	table := []struct {
+		pageReport *models.PageReport
+		expected   bool
+	}{
+		{&models.PageReport{Images: []models.Image{{URL: "https://example.com/img.jpg"}}}, false},
+		{&models.PageReport{Images: []models.Image{{URL: "http://example.com/img.jpg"}}}, true},
+		{&models.PageReport{Audios: []string{"http://example.com/audio.mp3"}}, true},
+		{&models.PageReport{Videos: []models.Video{{URL: "https://example.com/v.mp4", Poster: "http://example.com/p.jpg"}}}, true},
+		{&models.PageReport{Scripts: []string{"http://example.com/app.js"}}, false},
+	}
+
+	for _, test := range table {
+		test.pageReport.Crawled = true
+		test.pageReport.URL = pageURL
+		test.pageReport.ParsedURL = parsedURL
+		test.pageReport.MediaType = "text/html"
+		test.pageReport.StatusCode = 200
+
+		reportsIssue := reporter.Callback(test.pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != test.expected {
+			t.Errorf("TestPassiveMixedContent: reportsIssue should be %v", test.expected)
+		}
+	}
+}
+
+// Test the mixed content reporters with a page served over http.
+// The reporters should not report the issue.
+func TestMixedContentHTTPPage(t *testing.T) {
+	pageURL := "http://example.com"
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	pageReport := &models.PageReport{
+		Crawled:    true,
+		URL:        pageURL,
+		ParsedURL:  parsedURL,
+		MediaType:  "text/html",
+		StatusCode: 200,
+		Scripts:    []string{"http://example.com/app.js"},
+		Images:     []models.Image{{URL: "http://example.com/img.jpg"}},
+	}
+
+	reporters := []*models.PageIssueReporter{
+		page.NewActiveMixedContentReporter(),
+		page.NewPassiveMixedContentReporter(),
+	}
+
+	for _, reporter := range reporters {
+		if reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestMixedContentHTTPPage: reportsIssue should be false")
+		}
+	}
+}
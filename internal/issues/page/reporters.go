@@ -79,6 +79,16 @@ func GetAllReporters() []*models.PageIssueReporter {
 		NewMissingHSTSHeaderReporter(),
 		NewMissingCSPReporter(),
 		NewMissingContentTypeOptionsReporter(),
+		NewMissingReferrerPolicyReporter(),
+		NewMissingPermissionsPolicyReporter(),
+		NewMissingFrameOptionsReporter(),
+		NewUnsafeCSPReporter(),
+		NewMissingSRIReporter(),
+		NewCookieWithoutSecureReporter(),
+		NewCookieWithoutHttpOnlyReporter(),
+		NewCookieWithoutSameSiteReporter(),
+		NewCertificateHostnameMismatchReporter(),
+		NewWeakTLSVersionReporter(),
 
 		// Add timeout issue reporter
 		NewTimeoutReporter(),
@@ -95,6 +105,20 @@ func GetAllReporters() []*models.PageIssueReporter {
 		NewFormOnHTTPReporter(),
 		NewInsecureFormReporter(),
 
+		// Add mixed content reporters
+		NewActiveMixedContentReporter(),
+		NewPassiveMixedContentReporter(),
+
+		// Add structured data reporters
+		NewInvalidJSONLDReporter(),
+		NewStructuredDataMissingPropertiesReporter(),
+
+		// Add social tags reporters
+		NewMissingOGTitleReporter(),
+		NewMissingOGImageReporter(),
+		NewOGURLCanonicalMismatchReporter(),
+		NewOGImageRelativeReporter(),
+
 		// Add Viewport issue report
 		NewViewportTagReporter(),
 	}
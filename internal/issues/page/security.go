@@ -7,6 +7,7 @@ import (
 
 	"github.com/stjudewashere/seonaut/internal/issues/errors"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/urlutils"
 
 	"github.com/antchfx/htmlquery"
 	"golang.org/x/net/html"
@@ -86,3 +87,251 @@ func NewMissingContentTypeOptionsReporter() *models.PageIssueReporter {
 		Callback:  c,
 	}
 }
+
+// Returns a report_manager.PageIssueReporter with a callback function that
+// reports if the page's Referrer-Policy is missing by looking both in the Headers and meta tags.
+// The callback returns true if the policy does not exist.
+func NewMissingReferrerPolicyReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		referrerTag, err := htmlquery.QueryAll(htmlNode, "//head/meta[@name=\"referrer\"]")
+		if err != nil {
+			return false
+		}
+
+		return referrerTag == nil && header.Get("Referrer-Policy") == ""
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorMissingReferrerPolicy,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that
+// reports if the page's Permissions-Policy header is missing. The older Feature-Policy
+// header is accepted as well.
+func NewMissingPermissionsPolicyReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		return header.Get("Permissions-Policy") == "" && header.Get("Feature-Policy") == ""
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorMissingPermissionsPolicy,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page can be framed by any site. The callback returns true if the page has no X-Frame-Options
+// header and its CSP header has no frame-ancestors directive, which is ignored in meta tags.
+func NewMissingFrameOptionsReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		if header.Get("X-Frame-Options") != "" {
+			return false
+		}
+
+		for _, csp := range header.Values("Content-Security-Policy") {
+			if _, ok := cspDirectives(csp)["frame-ancestors"]; ok {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorMissingFrameOptions,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page's CSP, either in the headers or in meta tags, allows 'unsafe-inline' or wildcard sources
+// such as * or a bare scheme. 'unsafe-inline' is not reported in directives with a nonce or a
+// hash, as browsers ignore it in that case.
+func NewUnsafeCSPReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		policies := header.Values("Content-Security-Policy")
+
+		cspTags, err := htmlquery.QueryAll(htmlNode, "//head/meta[@http-equiv=\"Content-Security-Policy\"]")
+		if err != nil {
+			return false
+		}
+
+		for _, t := range cspTags {
+			policies = append(policies, htmlquery.SelectAttr(t, "content"))
+		}
+
+		for _, csp := range policies {
+			for _, sources := range cspDirectives(csp) {
+				if unsafeSources(sources) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorUnsafeCSP,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page loads cross-origin scripts or stylesheets without the integrity attribute, so the browser
+// can't check the files haven't been tampered with.
+func NewMissingSRIReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.MediaType != "text/html" {
+			return false
+		}
+
+		if pageReport.StatusCode < 200 || pageReport.StatusCode >= 300 {
+			return false
+		}
+
+		scripts, err := htmlquery.QueryAll(htmlNode, "//script[@src]")
+		if err != nil {
+			return false
+		}
+
+		styles, err := htmlquery.QueryAll(htmlNode, "//link[@rel=\"stylesheet\"][@href]")
+		if err != nil {
+			return false
+		}
+
+		for _, n := range append(scripts, styles...) {
+			if htmlquery.SelectAttr(n, "integrity") != "" {
+				continue
+			}
+
+			src := htmlquery.SelectAttr(n, "src")
+			if n.Data == "link" {
+				src = htmlquery.SelectAttr(n, "href")
+			}
+
+			u, err := urlutils.AbsoluteURL(src, htmlNode, pageReport.ParsedURL)
+			if err != nil {
+				continue
+			}
+
+			if u.Scheme != pageReport.ParsedURL.Scheme || u.Host != pageReport.ParsedURL.Host {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorMissingSRI,
+		Callback:  c,
+	}
+}
+
+// Returns the CSP directives mapped to their lowercased source lists.
+func cspDirectives(csp string) map[string][]string {
+	directives := make(map[string][]string)
+	for _, d := range strings.Split(csp, ";") {
+		fields := strings.Fields(strings.ToLower(d))
+		if len(fields) == 0 {
+			continue
+		}
+
+		directives[fields[0]] = fields[1:]
+	}
+
+	return directives
+}
+
+// Returns true if the source list allows any host or inline code without a nonce or hash.
+func unsafeSources(sources []string) bool {
+	unsafeInline, nonceOrHash := false, false
+	for _, s := range sources {
+		switch {
+		case s == "*", s == "http:", s == "https:":
+			return true
+		case s == "'unsafe-inline'":
+			unsafeInline = true
+		case strings.HasPrefix(s, "'nonce-"), strings.HasPrefix(s, "'sha"):
+			nonceOrHash = true
+		}
+	}
+
+	return unsafeInline && !nonceOrHash
+}
+
+// Days before the certificate's expiry date to start reporting it, used if the
+// reporter is created without a valid number of days.
+const defaultCertificateExpiryDays = 30
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page is served with a TLS certificate that expires within the specified number of days.
+// Expired certificates are reported as well.
+func NewCertificateExpiryReporter(days int) *models.PageIssueReporter {
+	if days <= 0 {
+		days = defaultCertificateExpiryDays
+	}
+
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if pageReport.Certificate == nil {
+			return false
+		}
+
+		return pageReport.Certificate.ExpiresWithin(days)
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorCertificateExpiring,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page is served with a TLS certificate that is not valid for the page's host name.
+func NewCertificateHostnameMismatchReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		return pageReport.Certificate != nil && pageReport.Certificate.HostnameMismatch
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorCertificateHostnameMismatch,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page is served using a deprecated TLS version, TLS 1.1 or older.
+func NewWeakTLSVersionReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		switch pageReport.TLSVersion {
+		case "SSLv3", "TLS 1.0", "TLS 1.1":
+			return true
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorWeakTLSVersion,
+		Callback:  c,
+	}
+}
@@ -2,8 +2,10 @@ package page_test
 
 import (
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/issues/errors"
 	"github.com/stjudewashere/seonaut/internal/issues/page"
@@ -200,3 +202,247 @@ func TestMissingContentTypeOptionsIssues(t *testing.T) {
 		t.Errorf("reportsIssue should be true")
 	}
 }
+
+// Test the CertificateExpiry reporter with certificates expiring at different dates.
+func TestCertificateExpiry(t *testing.T) {
+	reporter := page.NewCertificateExpiryReporter(30)
+	if reporter.ErrorType != errors.ErrorCertificateExpiring {
+		t.Errorf("TestCertificateExpiry: error type is not correct")
+	}
+
+	table := []struct {
+		name        string
+		certificate *models.Certificate
+		want        bool
+	}{
+		{"no certificate", nil, false},
+		{"expires in a year", &models.Certificate{NotAfter: time.Now().AddDate(1, 0, 0)}, false},
+		{"expires in a week", &models.Certificate{NotAfter: time.Now().AddDate(0, 0, 7)}, true},
+		{"expired", &models.Certificate{NotAfter: time.Now().AddDate(0, 0, -1)}, true},
+	}
+
+	for _, tt := range table {
+		pageReport := &models.PageReport{Certificate: tt.certificate}
+		if got := reporter.Callback(pageReport, &html.Node{}, &http.Header{}); got != tt.want {
+			t.Errorf("TestCertificateExpiry %s: want %v got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// Test the CertificateHostnameMismatch reporter with and without a mismatching certificate.
+func TestCertificateHostnameMismatch(t *testing.T) {
+	reporter := page.NewCertificateHostnameMismatchReporter()
+	if reporter.ErrorType != errors.ErrorCertificateHostnameMismatch {
+		t.Errorf("TestCertificateHostnameMismatch: error type is not correct")
+	}
+
+	pageReport := &models.PageReport{Certificate: &models.Certificate{Valid: true}}
+	if reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+		t.Errorf("TestCertificateHostnameMismatch: reportsIssue should be false")
+	}
+
+	pageReport.Certificate.HostnameMismatch = true
+	if !reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+		t.Errorf("TestCertificateHostnameMismatch: reportsIssue should be true")
+	}
+}
+
+// Test the WeakTLSVersion reporter with different TLS versions.
+func TestWeakTLSVersion(t *testing.T) {
+	reporter := page.NewWeakTLSVersionReporter()
+	if reporter.ErrorType != errors.ErrorWeakTLSVersion {
+		t.Errorf("TestWeakTLSVersion: error type is not correct")
+	}
+
+	table := map[string]bool{
+		"":        false,
+		"TLS 1.3": false,
+		"TLS 1.2": false,
+		"TLS 1.1": true,
+		"TLS 1.0": true,
+	}
+
+	for version, want := range table {
+		pageReport := &models.PageReport{TLSVersion: version}
+		if got := reporter.Callback(pageReport, &html.Node{}, &http.Header{}); got != want {
+			t.Errorf("TestWeakTLSVersion '%s': want %v got %v", version, want, got)
+		}
+	}
+}
+
+// Test the MissingReferrerPolicy reporter with pages with and without a Referrer-Policy
+// header or meta tag. The reporter should only report the page without it.
+func TestMissingReferrerPolicy(t *testing.T) {
+	reporter := page.NewMissingReferrerPolicyReporter()
+	if reporter.ErrorType != errors.ErrorMissingReferrerPolicy {
+		t.Errorf("TestMissingReferrerPolicy: error type is not correct")
+	}
+
+	pageReport := &models.PageReport{MediaType: "text/html"}
+
+	doc, err := html.Parse(strings.NewReader(`<html><head><title>Test</title></head></html>`))
+	if err != nil {
+		t.Errorf("error parsing html source")
+	}
+
+	if reporter.Callback(pageReport, doc, &http.Header{}) == false {
+		t.Errorf("TestMissingReferrerPolicy: reportsIssue should be true")
+	}
+
+	header := &http.Header{}
+	header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+	if reporter.Callback(pageReport, doc, header) == true {
+		t.Errorf("TestMissingReferrerPolicy: reportsIssue should be false with the header")
+	}
+
+	doc, err = html.Parse(strings.NewReader(`<html><head><meta name="referrer" content="no-referrer"></head></html>`))
+	if err != nil {
+		t.Errorf("error parsing html source")
+	}
+
+	if reporter.Callback(pageReport, doc, &http.Header{}) == true {
+		t.Errorf("TestMissingReferrerPolicy: reportsIssue should be false with the meta tag")
+	}
+}
+
+// Test the MissingPermissionsPolicy reporter with and without the Permissions-Policy header.
+func TestMissingPermissionsPolicy(t *testing.T) {
+	reporter := page.NewMissingPermissionsPolicyReporter()
+	if reporter.ErrorType != errors.ErrorMissingPermissionsPolicy {
+		t.Errorf("TestMissingPermissionsPolicy: error type is not correct")
+	}
+
+	pageReport := &models.PageReport{MediaType: "text/html"}
+
+	if reporter.Callback(pageReport, &html.Node{}, &http.Header{}) == false {
+		t.Errorf("TestMissingPermissionsPolicy: reportsIssue should be true")
+	}
+
+	header := &http.Header{}
+	header.Set("Permissions-Policy", "geolocation=()")
+	if reporter.Callback(pageReport, &html.Node{}, header) == true {
+		t.Errorf("TestMissingPermissionsPolicy: reportsIssue should be false")
+	}
+}
+
+// Test the MissingFrameOptions reporter with X-Frame-Options and CSP frame-ancestors headers.
+func TestMissingFrameOptions(t *testing.T) {
+	reporter := page.NewMissingFrameOptionsReporter()
+	if reporter.ErrorType != errors.ErrorMissingFrameOptions {
+		t.Errorf("TestMissingFrameOptions: error type is not correct")
+	}
+
+	pageReport := &models.PageReport{MediaType: "text/html"}
+
+	table := []struct {
+		header   string
+		value    string
+		expected bool
+	}{
+		{"", "", true},
+		{"X-Frame-Options", "DENY", false},
+		{"Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'", false},
+		{"Content-Security-Policy", "default-src 'self'", true},
+	}
+
+	for _, test := range table {
+		header := &http.Header{}
+		if test.header != "" {
+			header.Set(test.header, test.value)
+		}
+
+		reportsIssue := reporter.Callback(pageReport, &html.Node{}, header)
+		if reportsIssue != test.expected {
+			t.Errorf("TestMissingFrameOptions %s: %s reportsIssue should be %v", test.header, test.value, test.expected)
+		}
+	}
+}
+
+// Test the UnsafeCSP reporter with policies allowing unsafe-inline or wildcard sources.
+func TestUnsafeCSP(t *testing.T) {
+	reporter := page.NewUnsafeCSPReporter()
+	if reporter.ErrorType != errors.ErrorUnsafeCSP {
+		t.Errorf("TestUnsafeCSP: error type is not correct")
+	}
+
+	pageReport := &models.PageReport{MediaType: "text/html"}
+
+	doc, err := html.Parse(strings.NewReader(`<html><head><title>Test</title></head></html>`))
+	if err != nil {
+		t.Errorf("error parsing html source")
+	}
+
+	table := []struct {
+		csp      string
+		expected bool
+	}{
+		{"default-src 'self'", false},
+		{"default-src 'self'; script-src 'self' 'unsafe-inline'", true},
+		{"script-src 'unsafe-inline' 'nonce-abc123'", false},
+		{"img-src *", true},
+		{"script-src https:", true},
+		{"script-src https://cdn.example.com", false},
+	}
+
+	for _, test := range table {
+		header := &http.Header{}
+		header.Set("Content-Security-Policy", test.csp)
+
+		reportsIssue := reporter.Callback(pageReport, doc, header)
+		if reportsIssue != test.expected {
+			t.Errorf("TestUnsafeCSP %s: reportsIssue should be %v", test.csp, test.expected)
+		}
+	}
+
+	doc, err = html.Parse(strings.NewReader(`<html><head><meta http-equiv="Content-Security-Policy" content="style-src 'unsafe-inline'"></head></html>`))
+	if err != nil {
+		t.Errorf("error parsing html source")
+	}
+
+	if reporter.Callback(pageReport, doc, &http.Header{}) == false {
+		t.Errorf("TestUnsafeCSP: reportsIssue should be true with the meta tag")
+	}
+}
+
+// Test the MissingSRI reporter with same-origin and cross-origin scripts and stylesheets.
+// Only cross-origin resources without the integrity attribute should be reported.
+func TestMissingSRI(t *testing.T) {
+	reporter := page.NewMissingSRIReporter()
+	if reporter.ErrorType != errors.ErrorMissingSRI {
+		t.Errorf("TestMissingSRI: error type is not correct")
+	}
+
+	parsedURL, err := url.Parse("https://example.com/page")
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	pageReport := &models.PageReport{
+		ParsedURL:  parsedURL,
+		MediaType:  "text/html",
+		StatusCode: 200,
+	}
+
+	table := []struct {
+		source   string
+		expected bool
+	}{
+		{`<script src="/app.js"></script><link rel="stylesheet" href="https://example.com/style.css">`, false},
+		{`<script src="https://cdn.example.net/lib.js" integrity="sha384-abc" crossorigin="anonymous"></script>`, false},
+		{`<script src="https://cdn.example.net/lib.js"></script>`, true},
+		{`<link rel="stylesheet" href="https://fonts.example.net/font.css">`, true},
+		{`<script src="https://static.example.com/app.js"></script>`, true},
+	}
+
+	for _, test := range table {
+		doc, err := html.Parse(strings.NewReader("<html><head>" + test.source + "</head></html>"))
+		if err != nil {
+			t.Errorf("error parsing html source")
+		}
+
+		reportsIssue := reporter.Callback(pageReport, doc, &http.Header{})
+		if reportsIssue != test.expected {
+			t.Errorf("TestMissingSRI %s: reportsIssue should be %v", test.source, test.expected)
+		}
+	}
+}
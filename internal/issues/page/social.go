@@ -0,0 +1,115 @@
+package page
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Returns a report_manager.PageIssueReporter with a callback function that returns true if
+// the page is an html page with a 20x status code and doesn't have the og:title tag.
+func NewMissingOGTitleReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !socialPage(pageReport) {
+			return false
+		}
+
+		return pageReport.SocialTag("og:title") == ""
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorMissingOGTitle,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that returns true if
+// the page is an html page with a 20x status code and doesn't have the og:image tag.
+func NewMissingOGImageReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !socialPage(pageReport) {
+			return false
+		}
+
+		return pageReport.SocialTag("og:image") == ""
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorMissingOGImage,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that returns true if
+// the page's og:url is not the page's canonical URL, or the page URL if it has no canonical.
+// Relative og:url values are resolved before comparing them.
+func NewOGURLCanonicalMismatchReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !socialPage(pageReport) || pageReport.ParsedURL == nil {
+			return false
+		}
+
+		ogURL := pageReport.SocialTag("og:url")
+		if ogURL == "" {
+			return false
+		}
+
+		u, err := url.Parse(ogURL)
+		if err != nil {
+			return true
+		}
+
+		canonical := pageReport.URL
+		if pageReport.Canonical != "" {
+			canonical = pageReport.Canonical
+		}
+
+		return pageReport.ParsedURL.ResolveReference(u).String() != canonical
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorOGURLCanonicalMismatch,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that returns true if
+// the page's og:image is not an absolute URL, which most social networks can't load.
+func NewOGImageRelativeReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		if !socialPage(pageReport) {
+			return false
+		}
+
+		for _, t := range pageReport.SocialTags {
+			if t.Property != "og:image" || t.Content == "" {
+				continue
+			}
+
+			u, err := url.Parse(t.Content)
+			if err != nil || !u.IsAbs() {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorOGImageRelative,
+		Callback:  c,
+	}
+}
+
+// Returns true if the page report is a crawled html page with a 20x status code.
+func socialPage(pageReport *models.PageReport) bool {
+	if !pageReport.Crawled || pageReport.MediaType != "text/html" {
+		return false
+	}
+
+	return pageReport.StatusCode >= 200 && pageReport.StatusCode < 300
+}
@@ -0,0 +1,123 @@
+package page_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/issues/page"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Test the MissingOGTitle and MissingOGImage reporters with pages with and without the tags.
+func TestMissingOGTags(t *testing.T) {
+	table := []struct {
+		reporter  *models.PageIssueReporter
+		errorType int
+		property  string
+	}{
+		{page.NewMissingOGTitleReporter(), errors.ErrorMissingOGTitle, "og:title"},
+		{page.NewMissingOGImageReporter(), errors.ErrorMissingOGImage, "og:image"},
+	}
+
+	for _, test := range table {
+		if test.reporter.ErrorType != test.errorType {
+			t.Errorf("TestMissingOGTags: error type is not correct")
+		}
+
+		pageReport := &models.PageReport{
+			Crawled:    true,
+			MediaType:  "text/html",
+			StatusCode: 200,
+		}
+
+		if !test.reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestMissingOGTags: %s reportsIssue should be true", test.property)
+		}
+
+		pageReport.SocialTags = []models.SocialTag{{Property: test.property, Content: "content"}}
+		if test.reporter.Callback(pageReport, &html.Node{}, &http.Header{}) {
+			t.Errorf("TestMissingOGTags: %s reportsIssue should be false", test.property)
+		}
+	}
+}
+
+// Test the OGURLCanonicalMismatch reporter. The og:url is resolved against the page URL
+// and compared with the canonical, or with the page URL if there is no canonical.
+func TestOGURLCanonicalMismatch(t *testing.T) {
+	pageURL := "https://example.com/page"
+	parsedURL, err := url.Parse(pageURL)
+	if err != nil {
+		t.Errorf("Parse URL error: %v", err)
+	}
+
+	reporter := page.NewOGURLCanonicalMismatchReporter()
+	if reporter.ErrorType != errors.ErrorOGURLCanonicalMismatch {
+		t.Errorf("TestOGURLCanonicalMismatch: error type is not correct")
+	}
+
+	table := []struct {
+		ogURL     string
+		canonical string
+		expected  bool
+	}{
+		{"", "", false},
+		{"https://example.com/page", "", false},
+		{"/page", "", false},
+		{"https://example.com/other", "", true},
+		{"https://example.com/canonical", "https://example.com/canonical", false},
+		{"https://example.com/page", "https://example.com/canonical", true},
+	}
+
+	for _, test := range table {
+		pageReport := &models.PageReport{
+			Crawled:    true,
+			URL:        pageURL,
+			ParsedURL:  parsedURL,
+			MediaType:  "text/html",
+			StatusCode: 200,
+			Canonical:  test.canonical,
+			SocialTags: []models.SocialTag{{Property: "og:url", Content: test.ogURL}},
+		}
+
+		reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != test.expected {
+			t.Errorf("TestOGURLCanonicalMismatch: %s reportsIssue should be %v", test.ogURL, test.expected)
+		}
+	}
+}
+
+// Test the OGImageRelative reporter with absolute and relative og:image URLs.
+func TestOGImageRelative(t *testing.T) {
+	reporter := page.NewOGImageRelativeReporter()
+	if reporter.ErrorType != errors.ErrorOGImageRelative {
+		t.Errorf("TestOGImageRelative: error type is not correct")
+	}
+
+	table := []struct {
+		image    string
+		expected bool
+	}{
+		{"https://example.com/image.jpg", false},
+		{"/image.jpg", true},
+		{"image.jpg", true},
+		{"//cdn.example.com/image.jpg", true},
+	}
+
+	for _, test := range table {
+		pageReport := &models.PageReport{
+			Crawled:    true,
+			MediaType:  "text/html",
+			StatusCode: 200,
+			SocialTags: []models.SocialTag{{Property: "og:image", Content: test.image}},
+		}
+
+		reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != test.expected {
+			t.Errorf("TestOGImageRelative: %s reportsIssue should be %v", test.image, test.expected)
+		}
+	}
+}
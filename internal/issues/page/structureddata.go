@@ -0,0 +1,114 @@
+package page
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Properties a schema.org item needs to be eligible for rich results. The item must have all
+// the properties in the all list and, if it is not empty, at least one in the oneOf list.
+var requiredProperties = map[string]struct {
+	all   []string
+	oneOf []string
+}{
+	"Product":        {all: []string{"name"}, oneOf: []string{"offers", "review", "aggregateRating"}},
+	"Article":        {all: []string{"headline"}},
+	"NewsArticle":    {all: []string{"headline"}},
+	"BlogPosting":    {all: []string{"headline"}},
+	"BreadcrumbList": {all: []string{"itemListElement"}},
+	"Organization":   {all: []string{"name"}},
+	"FAQPage":        {all: []string{"mainEntity"}},
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if
+// the page has JSON-LD scripts that can't be parsed.
+func NewInvalidJSONLDReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		for _, d := range pageReport.StructuredData {
+			if d.Format == models.StructuredDataJSONLD && d.Error != "" {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorInvalidJSONLD,
+		Callback:  c,
+	}
+}
+
+// Returns a report_manager.PageIssueReporter with a callback function that reports if the
+// page has Product, Article, BreadcrumbList, Organization or FAQPage structured data items
+// without their required properties.
+func NewStructuredDataMissingPropertiesReporter() *models.PageIssueReporter {
+	c := func(pageReport *models.PageReport, htmlNode *html.Node, header *http.Header) bool {
+		for _, d := range pageReport.StructuredData {
+			if d.Error != "" {
+				continue
+			}
+
+			var item map[string]any
+			if err := json.Unmarshal([]byte(d.Raw), &item); err != nil {
+				continue
+			}
+
+			for _, t := range strings.Split(d.Type, ",") {
+				required, ok := requiredProperties[t]
+				if ok && !hasRequiredProperties(item, required.all, required.oneOf) {
+					return true
+				}
+			}
+		}
+
+		return false
+	}
+
+	return &models.PageIssueReporter{
+		ErrorType: errors.ErrorStructuredDataProperties,
+		Callback:  c,
+	}
+}
+
+// Returns true if the item has all the properties in the all slice and at least one of the
+// properties in the oneOf slice, unless it is empty.
+func hasRequiredProperties(item map[string]any, all, oneOf []string) bool {
+	for _, p := range all {
+		if !hasProperty(item, p) {
+			return false
+		}
+	}
+
+	if len(oneOf) == 0 {
+		return true
+	}
+
+	for _, p := range oneOf {
+		if hasProperty(item, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns true if the item has the property and its value is not empty.
+func hasProperty(item map[string]any, name string) bool {
+	switch v := item[name].(type) {
+	case nil:
+		return false
+	case string:
+		return strings.TrimSpace(v) != ""
+	case []any:
+		return len(v) > 0
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package page_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/issues/errors"
+	"github.com/stjudewashere/seonaut/internal/issues/page"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Test the InvalidJSONLD reporter with valid and invalid JSON-LD items.
+// The reporter should only report the page with the invalid item.
+func TestInvalidJSONLD(t *testing.T) {
+	reporter := page.NewInvalidJSONLDReporter()
+	if reporter.ErrorType != errors.ErrorInvalidJSONLD {
+		t.Errorf("TestInvalidJSONLD: error type is not correct")
+	}
+
+	valid := &models.PageReport{
+		StructuredData: []models.StructuredData{
+			{Format: models.StructuredDataJSONLD, Type: "Organization", Raw: `{"@type":"Organization","name":"Example"}`},
+		},
+	}
+
+	if reporter.Callback(valid, &html.Node{}, &http.Header{}) {
+		t.Errorf("TestInvalidJSONLD: reportsIssue should be false")
+	}
+
+	invalid := &models.PageReport{
+		StructuredData: []models.StructuredData{
+			{Format: models.StructuredDataJSONLD, Raw: `{"@type": "Product",}`, Error: "invalid character '}'"},
+		},
+	}
+
+	if !reporter.Callback(invalid, &html.Node{}, &http.Header{}) {
+		t.Errorf("TestInvalidJSONLD: reportsIssue should be true")
+	}
+}
+
+// Test the StructuredDataMissingProperties reporter with items of the supported types.
+func TestStructuredDataMissingProperties(t *testing.T) {
+	reporter := page.NewStructuredDataMissingPropertiesReporter()
+	if reporter.ErrorType != errors.ErrorStructuredDataProperties {
+		t.Errorf("TestStructuredDataMissingProperties: error type is not correct")
+	}
+
+	table := []struct {
+		t        string
+		raw      string
+		expected bool
+	}{
+		{"Product", `{"@type":"Product","name":"Example","offers":{"@type":"Offer","price":"10"}}`, false},
+		{"Product", `{"@type":"Product","name":"Example"}`, true},
+		{"Product", `{"@type":"Product","offers":{"@type":"Offer"}}`, true},
+		{"Article", `{"@type":"Article","headline":"Title"}`, false},
+		{"Article,NewsArticle", `{"@type":["Article","NewsArticle"],"headline":""}`, true},
+		{"BreadcrumbList", `{"@type":"BreadcrumbList","itemListElement":[]}`, true},
+		{"Organization", `{"@type":"Organization","name":"Example"}`, false},
+		{"FAQPage", `{"@type":"FAQPage"}`, true},
+		{"WebPage", `{"@type":"WebPage"}`, false},
+	}
+
+	for _, test := range table {
+		pageReport := &models.PageReport{
+			StructuredData: []models.StructuredData{{Format: models.StructuredDataJSONLD, Type: test.t, Raw: test.raw}},
+		}
+
+		reportsIssue := reporter.Callback(pageReport, &html.Node{}, &http.Header{})
+		if reportsIssue != test.expected {
+			t.Errorf("TestStructuredDataMissingProperties %s: reportsIssue should be %v", test.raw, test.expected)
+		}
+	}
+}
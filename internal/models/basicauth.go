@@ -1,6 +1,8 @@
 package models
 
+// BasicAuth holds the credentials entered when a crawl is started. They are never stored.
 type BasicAuth struct {
-	AuthUser string
-	AuthPass string
+	AuthUser  string
+	AuthPass  string
+	LoginPass string // Password of the project's form login.
 }
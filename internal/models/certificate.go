@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Certificate holds the details of the TLS certificate of one of the crawled hosts.
+// PageReportId is the first page report crawled in the host.
+type Certificate struct {
+	CrawlId          int64
+	PageReportId     int64
+	Host             string
+	Subject          string
+	Issuer           string
+	NotBefore        time.Time
+	NotAfter         time.Time
+	DNSNames         []string
+	Valid            bool
+	HostnameMismatch bool
+	Error            string
+}
+
+// ExpiresWithin returns true if the certificate expires within the specified number of days.
+// Expired certificates are included.
+func (c *Certificate) ExpiresWithin(days int) bool {
+	return time.Now().AddDate(0, 0, days).After(c.NotAfter)
+}
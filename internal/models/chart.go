@@ -8,3 +8,14 @@ type (
 
 	Chart []ChartItem
 )
+
+// TimingDistribution holds the number of page reports by duration range, in milliseconds,
+// of one of the request phases.
+type TimingDistribution struct {
+	Phase    string
+	Under100 int
+	Under300 int
+	Under800 int
+	Under2s  int
+	Over2s   int
+}
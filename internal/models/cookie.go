@@ -0,0 +1,10 @@
+package models
+
+// Cookie is a cookie set by a page with the Set-Cookie header.
+type Cookie struct {
+	Name     string
+	Domain   string // The cookie's Domain attribute or the page's host if it doesn't have one.
+	Secure   bool
+	HttpOnly bool
+	SameSite string // Value of the SameSite attribute, empty if it is missing.
+}
@@ -4,10 +4,20 @@ import (
 	"time"
 )
 
+// CrawlMode is the way the crawler discovers the URLs to crawl.
+type CrawlMode int
+
+const (
+	CrawlModeSpider        CrawlMode = iota // Follow the links starting from the project's URL.
+	CrawlModeList                           // Crawl only the URLs in a list.
+	CrawlModeListRedirects                  // Crawl the URLs in a list and their redirect targets.
+)
+
 type Crawl struct {
 	Id        int64
 	ProjectId int64
 	Crawling  bool
+	Mode      CrawlMode
 
 	URL                   string
 	Start                 time.Time
@@ -29,4 +39,8 @@ type Crawl struct {
 	ExternalNoFollowLinks int
 	SponsoredLinks        int
 	UGCLinks              int
+	DepthLimited          int // URLs discovered beyond the project's max depth
+	OutOfScope            int // URLs discovered but excluded by the project's scope rules
+	Throttled             int // 429 and 503 responses received while the site was rate limiting the crawler
+	LoginFailed           bool
 }
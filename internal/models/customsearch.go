@@ -0,0 +1,21 @@
+package models
+
+type (
+	// CustomSearchCount is the number of pages matching one of the project's custom search rules.
+	CustomSearchCount struct {
+		Name  string
+		Rule  string // The rule as it is set in the project, empty if it was removed after the crawl.
+		Count int
+	}
+
+	CustomSearchView struct {
+		ProjectView *ProjectView
+		Counts      []CustomSearchCount
+	}
+
+	CustomSearchPagesView struct {
+		ProjectView   *ProjectView
+		Name          string
+		PaginatorView PaginatorView
+	}
+)
@@ -0,0 +1,60 @@
+package models
+
+// Types of changes between two crawls.
+const (
+	DiffAdded        = "added"
+	DiffRemoved      = "removed"
+	DiffStatusCode   = "status_code"
+	DiffTitle        = "title"
+	DiffDescription  = "description"
+	DiffH1           = "h1"
+	DiffCanonical    = "canonical"
+	DiffIndexability = "indexability"
+
+	DiffIssueIntroduced = "issue_introduced"
+	DiffIssueResolved   = "issue_resolved"
+)
+
+// DiffChange is a change of an URL between two crawls. Old and New contain
+// the value in the older and the newer crawl respectively.
+type DiffChange struct {
+	Type string
+	URL  string
+	Old  string
+	New  string
+}
+
+// DiffCount is the number of changes of an specific type.
+type DiffCount struct {
+	Type  string
+	Count int
+}
+
+// DiffIssue contains the number of issues of an specific type that were
+// introduced or resolved between two crawls.
+type DiffIssue struct {
+	ErrorType  string
+	Priority   int
+	Introduced int
+	Resolved   int
+}
+
+type CrawlDiff struct {
+	From    Crawl
+	To      Crawl
+	Changes []DiffCount
+	Issues  []DiffIssue
+}
+
+type DiffPaginatorView struct {
+	Paginator Paginator
+	Changes   []DiffChange
+}
+
+type CrawlDiffView struct {
+	ProjectView   *ProjectView
+	Crawls        []Crawl
+	Diff          *CrawlDiff
+	Type          string
+	PaginatorView DiffPaginatorView
+}
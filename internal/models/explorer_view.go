@@ -4,4 +4,6 @@ type ExplorerView struct {
 	ProjectView   *ProjectView
 	Term          string
 	PaginatorView PaginatorView
+	Extractors    []string           // Names of the custom extraction rules with values in the crawl.
+	Extractions   map[int64][]string // Extracted values by PageReport Id, in the same order as the Extractors.
 }
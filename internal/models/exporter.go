@@ -49,3 +49,28 @@ type ExportIssue struct {
 	Type     string
 	Priority int
 }
+
+type ExportRedirectHop struct {
+	Origin   string
+	Position int
+	RedirectHop
+}
+
+type ExportCookie struct {
+	Origin string
+	Cookie
+}
+
+// ExportResource is a script or stylesheet loaded by the Origin page.
+type ExportResource struct {
+	Origin string
+	Type   string // Either "script" or "style".
+	URL    string
+}
+
+type ExportStructuredData struct {
+	Origin  string
+	Types   string
+	Items   int
+	Invalid int
+}
@@ -0,0 +1,28 @@
+package models
+
+import "strings"
+
+// Extraction is a value extracted from a page with one of the project's custom extraction rules.
+type Extraction struct {
+	Name  string
+	Value string
+}
+
+// ExtractedValues returns the values extracted with each of the named rules, in the same order.
+// The values of the rules that matched more than once are joined, and the ones of the rules that
+// didn't match are empty.
+func (p *PageReport) ExtractedValues(names []string) []string {
+	values := make([]string, len(names))
+	for i, name := range names {
+		matches := []string{}
+		for _, e := range p.Extractions {
+			if e.Name == name {
+				matches = append(matches, e.Value)
+			}
+		}
+
+		values[i] = strings.Join(matches, " | ")
+	}
+
+	return values
+}
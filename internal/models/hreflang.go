@@ -3,4 +3,5 @@ package models
 type Hreflang struct {
 	URL  string
 	Lang string
+	Key  string // Normalized URL, used to join the hreflang with its target.
 }
@@ -0,0 +1,60 @@
+package models
+
+import "strings"
+
+// ActiveMixedContent returns the scripts, stylesheets and iframes loaded with the http
+// scheme in a page served over https. Browsers block this kind of mixed content.
+func (p *PageReport) ActiveMixedContent() []string {
+	if !p.secure() {
+		return nil
+	}
+
+	var urls []string
+	for _, resources := range [][]string{p.Scripts, p.Styles, p.Iframes} {
+		urls = append(urls, insecureURLs(resources)...)
+	}
+
+	return urls
+}
+
+// PassiveMixedContent returns the images, audios and videos loaded with the http scheme
+// in a page served over https. Browsers may load or upgrade it but flag the page as not secure.
+func (p *PageReport) PassiveMixedContent() []string {
+	if !p.secure() {
+		return nil
+	}
+
+	resources := []string{}
+	for _, i := range p.Images {
+		resources = append(resources, i.URL)
+	}
+
+	resources = append(resources, p.Audios...)
+
+	for _, v := range p.Videos {
+		resources = append(resources, v.URL, v.Poster)
+	}
+
+	return insecureURLs(resources)
+}
+
+// Returns true if the page was served with the https scheme.
+func (p *PageReport) secure() bool {
+	return p.ParsedURL != nil && p.ParsedURL.Scheme == "https"
+}
+
+// Returns the URLs using the http scheme, without duplicates.
+func insecureURLs(urls []string) []string {
+	var insecure []string
+	seen := map[string]bool{}
+	for _, u := range urls {
+		if !strings.HasPrefix(u, "http://") || seen[u] {
+			continue
+		}
+
+		seen[u] = true
+		insecure = append(insecure, u)
+	}
+
+	return insecure
+}
@@ -0,0 +1,13 @@
+package models
+
+// NearDuplicate is a page report whose main text is similar to other pages of the crawl.
+// Match is the closest page report and Similarity the percentage of the fingerprint bits
+// they have in common. The near-duplicate pages are grouped in clusters identified by
+// the id of their first page report.
+type NearDuplicate struct {
+	PageReportId int64
+	Match        PageReport
+	Similarity   int
+	Cluster      int64
+	ClusterSize  int // Number of pages in the cluster, only loaded to display the near-duplicate.
+}
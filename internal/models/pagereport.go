@@ -9,6 +9,7 @@ type PageReport struct {
 	URL                string
 	ParsedURL          *url.URL
 	RedirectURL        string
+	RedirectKey        string // Normalized RedirectURL, used to join the redirect with its target.
 	Refresh            string
 	StatusCode         int
 	ContentType        string
@@ -33,12 +34,30 @@ type PageReport struct {
 	Iframes            []string
 	Audios             []string
 	Videos             []Video
+	Cookies            []Cookie
+	StructuredData     []StructuredData
+	SocialTags         []SocialTag
+	Extractions        []Extraction
+	CustomSearch       []string // Names of the custom search rules matched by the page.
 	BlockedByRobotstxt bool
 	Crawled            bool
 	InSitemap          bool
 	InternalLinks      []InternalLink
 	Depth              int
 	BodyHash           string
+	SimHash            uint64 // Fingerprint of the page's main text, zero if it is too short to compare.
 	Timeout            bool
 	TTFB               int
+	Attempts           int // Number of times the URL was requested, more than one if it was retried.
+
+	// Duration in milliseconds of each phase of the request. The DNS lookup, TCP connect and
+	// TLS handshake are zero if the request reused an open connection.
+	DNSLookup    int
+	TCPConnect   int
+	TLSHandshake int
+	Download     int
+	Protocol     string // Negotiated protocol, for instance HTTP/1.1 or HTTP/2.0.
+	TLSVersion   string
+
+	Certificate *Certificate // The host's TLS certificate, it is not stored with the page report.
 }
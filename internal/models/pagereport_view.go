@@ -1,9 +1,15 @@
 package models
 
 type PageReportView struct {
-	PageReport PageReport
-	ErrorTypes []string
-	InLinks    []InternalLink
-	Redirects  []PageReport
-	Paginator  Paginator
+	PageReport          PageReport
+	ErrorTypes          []string
+	InLinks             []InternalLink
+	Redirects           []PageReport
+	RedirectChain       RedirectChain
+	Certificate         *Certificate
+	ActiveMixedContent  []string
+	PassiveMixedContent []string
+	NearDuplicate       NearDuplicate
+	SocialPreview       SocialPreview
+	Paginator           Paginator
 }
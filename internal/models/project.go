@@ -19,4 +19,31 @@ type Project struct {
 	CheckExternalLinks bool
 	Archive            bool
 	UserAgent          string
+	CrawlRetention     int
+	CrawlSchedule      string    // Cron expression or descriptor, empty if the project is not scheduled.
+	NextCrawl          time.Time // Next scheduled crawl, zero if the project is not scheduled.
+	CrawlWorkers       int       // Number of concurrent requests.
+	CrawlMinDelay      int       // Min delay in milliseconds before each request.
+	CrawlMaxDelay      int       // Max delay in milliseconds before each request.
+	CrawlMaxRPS        float64   // Max requests per second, zero if not limited.
+	CrawlTimeout       int       // Crawl timeout in minutes.
+	CrawlLimit         int       // Max number of URLs to crawl.
+	MaxDepth           int       // Max depth from the start URL, zero if there's no limit.
+	IncludeRules       string    // Newline separated include rules, path prefixes or regular expressions.
+	ExcludeRules       string    // Newline separated exclude rules, path prefixes or regular expressions.
+	IgnoredParams      string    // Comma separated query parameters removed from the URLs.
+	RemoveIndex        bool      // Remove the default index documents from the URLs.
+	CustomHeaders      string    // Newline separated headers in the "Name: value" format.
+	CustomCookies      string    // Newline separated cookies in the "name=value" format.
+	LoginURL           string    // URL of the login form, empty if the project doesn't use form login.
+	LoginUserField     string    // Name of the login form's username field.
+	LoginPassField     string    // Name of the login form's password field.
+	LoginUser          string
+	LoginSuccess       string // Text found in the page shown after a successful login.
+	LoginLoggedOut     string // Text found in the responses when the session is lost.
+	Proxy              string // Proxy URL, empty to use the default proxy if there's one.
+	RequestTimeout     int    // Request timeout in seconds.
+	RequestRetries     int    // Number of retries after a timeout or a transient network error.
+	Extractors         string // Newline separated extraction rules in the "name type expression" format.
+	CustomSearch       string // Newline separated search rules in the "name operator [scope] pattern" format.
 }
@@ -0,0 +1,45 @@
+package models
+
+// RedirectHop is a single URL in a redirect chain. Refresh is true if the URL redirects
+// with a meta refresh tag instead of an HTTP redirect. CrossScheme and CrossHost are true
+// if the hop's scheme or host is different from the previous hop's.
+type RedirectHop struct {
+	URL         string
+	StatusCode  int
+	Refresh     bool
+	CrossScheme bool
+	CrossHost   bool
+}
+
+// RedirectChain holds all the hops followed from a redirecting page report, the first hop
+// being the page report itself. The last hop is the chain's destination, or the URL that
+// was already visited if the chain is a loop.
+type RedirectChain struct {
+	PageReportId int64
+	Hops         []RedirectHop
+}
+
+// Loop returns true if the chain's last hop is a URL already visited in the chain.
+func (c RedirectChain) Loop() bool {
+	if len(c.Hops) < 2 {
+		return false
+	}
+
+	last := c.Hops[len(c.Hops)-1].URL
+	for _, h := range c.Hops[:len(c.Hops)-1] {
+		if h.URL == last {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Destination returns the chain's last hop.
+func (c RedirectChain) Destination() RedirectHop {
+	if len(c.Hops) == 0 {
+		return RedirectHop{}
+	}
+
+	return c.Hops[len(c.Hops)-1]
+}
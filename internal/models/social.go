@@ -0,0 +1,69 @@
+package models
+
+import "strings"
+
+// SocialTag is an Open Graph or Twitter Card meta tag.
+type SocialTag struct {
+	Property string // The tag's property, for instance og:title or twitter:card.
+	Content  string
+	URL      string // The absolute URL of the image tags, empty for the rest of tags.
+}
+
+// SocialPreview has the data social networks use to show a page when it is shared.
+type SocialPreview struct {
+	Title       string
+	Description string
+	Image       string
+	URL         string
+	SiteName    string
+	Card        string
+}
+
+// SocialTag returns the content of the first social tag with the property, or an empty string
+// if the page doesn't have it.
+func (p *PageReport) SocialTag(property string) string {
+	for _, t := range p.SocialTags {
+		if t.Property == property {
+			return t.Content
+		}
+	}
+
+	return ""
+}
+
+// NewSocialPreview returns the page's SocialPreview, falling back to the Twitter Card tags if
+// the Open Graph tags are missing and to the page's title, description and URL if both are.
+func NewSocialPreview(p *PageReport) SocialPreview {
+	preview := SocialPreview{
+		Title:       firstNonEmpty(p.SocialTag("og:title"), p.SocialTag("twitter:title"), p.Title),
+		Description: firstNonEmpty(p.SocialTag("og:description"), p.SocialTag("twitter:description"), p.Description),
+		URL:         firstNonEmpty(p.SocialTag("og:url"), p.Canonical, p.URL),
+		SiteName:    p.SocialTag("og:site_name"),
+		Card:        p.SocialTag("twitter:card"),
+	}
+
+	for _, property := range []string{"og:image", "twitter:image"} {
+		for _, t := range p.SocialTags {
+			if t.Property == property && preview.Image == "" {
+				preview.Image = t.URL
+			}
+		}
+	}
+
+	if preview.SiteName == "" && p.ParsedURL != nil {
+		preview.SiteName = strings.TrimPrefix(p.ParsedURL.Hostname(), "www.")
+	}
+
+	return preview
+}
+
+// Returns the first non empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,16 @@
+package models
+
+const (
+	StructuredDataJSONLD    = "json-ld"
+	StructuredDataMicrodata = "microdata"
+)
+
+// StructuredData is a structured data item found in a page, either in a JSON-LD script or
+// in microdata attributes. Microdata items are converted to JSON so both formats are
+// stored the same way.
+type StructuredData struct {
+	Format string
+	Type   string // The item's schema types separated by commas, for instance "Product".
+	Raw    string // The item as JSON.
+	Error  string // The error found parsing the item, empty if it is valid.
+}
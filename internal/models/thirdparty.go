@@ -0,0 +1,9 @@
+package models
+
+// ThirdPartyDomain groups the scripts or stylesheets a site loads from a third-party domain.
+type ThirdPartyDomain struct {
+	Domain    string
+	Type      string // Either "script" or "style".
+	Pages     int    // Number of distinct pages loading resources from the domain.
+	Resources []ExportResource
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type CertificateRepository struct {
+	DB *sql.DB
+}
+
+// SaveCertificate saves the TLS certificate of one of the crawled hosts.
+func (ds *CertificateRepository) SaveCertificate(c *models.Certificate) error {
+	query := `
+		INSERT INTO certificates (
+			crawl_id,
+			pagereport_id,
+			host,
+			subject,
+			issuer,
+			not_before,
+			not_after,
+			dns_names,
+			valid,
+			hostname_mismatch,
+			error
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := ds.DB.Exec(
+		query,
+		c.CrawlId,
+		c.PageReportId,
+		c.Host,
+		Truncate(c.Subject, 1024),
+		Truncate(c.Issuer, 1024),
+		c.NotBefore,
+		c.NotAfter,
+		strings.Join(c.DNSNames, "\n"),
+		c.Valid,
+		c.HostnameMismatch,
+		Truncate(c.Error, 1024),
+	)
+
+	return err
+}
+
+// FindCertificate returns the TLS certificate of a host in the specified crawl.
+func (ds *CertificateRepository) FindCertificate(host string, cid int64) (*models.Certificate, error) {
+	query := `
+		SELECT
+			crawl_id,
+			pagereport_id,
+			host,
+			subject,
+			issuer,
+			not_before,
+			not_after,
+			dns_names,
+			valid,
+			hostname_mismatch,
+			error
+		FROM certificates
+		WHERE crawl_id = ? AND host = ?
+		LIMIT 1`
+
+	c := &models.Certificate{}
+	var notBefore, notAfter sql.NullTime
+	var dnsNames sql.NullString
+	err := ds.DB.QueryRow(query, cid, host).Scan(
+		&c.CrawlId,
+		&c.PageReportId,
+		&c.Host,
+		&c.Subject,
+		&c.Issuer,
+		&notBefore,
+		&notAfter,
+		&dnsNames,
+		&c.Valid,
+		&c.HostnameMismatch,
+		&c.Error,
+	)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			log.Printf("FindCertificate: %v\n", err)
+		}
+		return nil, err
+	}
+
+	c.NotBefore = notBefore.Time
+	c.NotAfter = notAfter.Time
+	if dnsNames.String != "" {
+		c.DNSNames = strings.Split(dnsNames.String, "\n")
+	}
+
+	return c, nil
+}
@@ -4,7 +4,6 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"strings"
 	"time"
 
 	"github.com/stjudewashere/seonaut/internal/models"
@@ -16,10 +15,10 @@ type CrawlRepository struct {
 
 // SaveCrawl inserts a new crawl into the database and returns a new Crawl model with
 // the data provided by the project.
-func (ds *CrawlRepository) SaveCrawl(p models.Project) (*models.Crawl, error) {
-	stmt, _ := ds.DB.Prepare("INSERT INTO crawls (project_id) VALUES (?)")
+func (ds *CrawlRepository) SaveCrawl(p models.Project, mode models.CrawlMode) (*models.Crawl, error) {
+	stmt, _ := ds.DB.Prepare("INSERT INTO crawls (project_id, mode) VALUES (?, ?)")
 	defer stmt.Close()
-	res, err := stmt.Exec(p.Id)
+	res, err := stmt.Exec(p.Id, mode)
 
 	if err != nil {
 		return nil, err
@@ -33,43 +32,47 @@ func (ds *CrawlRepository) SaveCrawl(p models.Project) (*models.Crawl, error) {
 	return &models.Crawl{
 		Id:        cid,
 		ProjectId: p.Id,
+		Mode:      mode,
 		URL:       p.URL,
 		Start:     time.Now(),
 	}, nil
 }
 
-// GetLastCrawl returns a Crawl model with the last crawl stored for an specific project.
-func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
-	query := `
-		SELECT
-			id,
-			start,
-			end,
-			total_urls,
-			total_issues,
-			critical_issues,
-			alert_issues,
-			warning_issues,
-			issues_end,
-			robotstxt_exists,
-			sitemap_exists,
-			sitemap_blocked,
-			links_internal_follow,
-			links_internal_nofollow,
-			links_external_follow,
-			links_external_nofollow,
-			links_sponsored,
-			links_ugc
-		FROM crawls
-		WHERE project_id = ?
-		ORDER BY start DESC LIMIT 1`
-
-	row := ds.DB.QueryRow(query, p.Id)
-
+// crawlColumns is the list of columns scanned by scanCrawl.
+const crawlColumns = `
+	id,
+	project_id,
+	start,
+	end,
+	total_urls,
+	total_issues,
+	critical_issues,
+	alert_issues,
+	warning_issues,
+	issues_end,
+	robotstxt_exists,
+	sitemap_exists,
+	sitemap_blocked,
+	links_internal_follow,
+	links_internal_nofollow,
+	links_external_follow,
+	links_external_nofollow,
+	links_sponsored,
+	links_ugc,
+	depth_limited,
+	out_of_scope,
+	throttled,
+	login_failed,
+	mode`
+
+// scanCrawl scans a row selected with the crawlColumns into a Crawl model. The crawl is
+// considered to be crawling until both, the end and issues_end fields, are set.
+func scanCrawl(s scanner) (models.Crawl, error) {
 	var endTime, issuesEndTime sql.NullTime
 	crawl := models.Crawl{Crawling: true}
-	err := row.Scan(
+	err := s.Scan(
 		&crawl.Id,
+		&crawl.ProjectId,
 		&crawl.Start,
 		&endTime, // &crawl.End,
 		&crawl.TotalURLs,
@@ -87,10 +90,12 @@ func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
 		&crawl.ExternalNoFollowLinks,
 		&crawl.SponsoredLinks,
 		&crawl.UGCLinks,
+		&crawl.DepthLimited,
+		&crawl.OutOfScope,
+		&crawl.Throttled,
+		&crawl.LoginFailed,
+		&crawl.Mode,
 	)
-	if err != nil && err != sql.ErrNoRows {
-		log.Printf("GetLastCrawl project id %d: %v\n", p.Id, err)
-	}
 
 	if endTime.Valid && issuesEndTime.Valid {
 		crawl.End = endTime.Time
@@ -98,9 +103,88 @@ func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
 		crawl.Crawling = false
 	}
 
+	return crawl, err
+}
+
+// GetLastCrawl returns a Crawl model with the last crawl stored for an specific project.
+func (ds *CrawlRepository) GetLastCrawl(p *models.Project) models.Crawl {
+	query := `
+		SELECT ` + crawlColumns + `
+		FROM crawls
+		WHERE project_id = ?
+		ORDER BY start DESC LIMIT 1`
+
+	row := ds.DB.QueryRow(query, p.Id)
+
+	crawl, err := scanCrawl(row)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("GetLastCrawl project id %d: %v\n", p.Id, err)
+	}
+
 	return crawl
 }
 
+// FindCrawlById returns the project's crawl with the specified id. It returns an error if
+// the crawl doesn't exist or if its data has been pruned.
+func (ds *CrawlRepository) FindCrawlById(p *models.Project, cid int64) (models.Crawl, error) {
+	query := `
+		SELECT ` + crawlColumns + `
+		FROM crawls
+		WHERE id = ? AND project_id = ? AND pruned = 0`
+
+	row := ds.DB.QueryRow(query, cid, p.Id)
+
+	return scanCrawl(row)
+}
+
+// GetKeptCrawls returns a slice with the project's finished crawls that still have their data,
+// starting with the most recent crawl.
+func (ds *CrawlRepository) GetKeptCrawls(p *models.Project) []models.Crawl {
+	query := `
+		SELECT ` + crawlColumns + `
+		FROM crawls
+		WHERE project_id = ? AND pruned = 0 AND issues_end IS NOT NULL
+		ORDER BY id DESC`
+
+	crawls := []models.Crawl{}
+	rows, err := ds.DB.Query(query, p.Id)
+	if err != nil {
+		log.Printf("GetKeptCrawls: %v\n", err)
+		return crawls
+	}
+
+	for rows.Next() {
+		crawl, err := scanCrawl(rows)
+		if err != nil {
+			log.Printf("GetKeptCrawls: %v\n", err)
+			continue
+		}
+
+		crawls = append(crawls, crawl)
+	}
+
+	return crawls
+}
+
+// PruneCrawls deletes the data of the project's finished crawls, keeping the data of the
+// most recent ones. The number of crawls to be kept is specified in the keep parameter.
+// The pruned crawls are not deleted so they are still available in the crawl history.
+func (ds *CrawlRepository) PruneCrawls(p *models.Project, keep int) {
+	crawls := ds.GetKeptCrawls(p)
+	if len(crawls) <= keep {
+		return
+	}
+
+	for _, crawl := range crawls[keep:] {
+		ds.DeleteCrawlData(&crawl)
+
+		_, err := ds.DB.Exec("UPDATE crawls SET pruned = 1 WHERE id = ?", crawl.Id)
+		if err != nil {
+			log.Printf("PruneCrawls: cid %d %v\n", crawl.Id, err)
+		}
+	}
+}
+
 // GetLastCrawls returns a slice with a number of crawls for the specific project. The number of crawls
 // to be returned is specified with the limit parameter.
 func (ds *CrawlRepository) GetLastCrawls(p models.Project, limit int) []models.Crawl {
@@ -193,6 +277,14 @@ func (ds *CrawlRepository) DeleteCrawlData(crawl *models.Crawl) {
 	deleteFunc(crawl.Id, "iframes")
 	deleteFunc(crawl.Id, "audios")
 	deleteFunc(crawl.Id, "videos")
+	deleteFunc(crawl.Id, "redirect_hops")
+	deleteFunc(crawl.Id, "certificates")
+	deleteFunc(crawl.Id, "cookies")
+	deleteFunc(crawl.Id, "structured_data")
+	deleteFunc(crawl.Id, "social_tags")
+	deleteFunc(crawl.Id, "extractions")
+	deleteFunc(crawl.Id, "custom_search")
+	deleteFunc(crawl.Id, "near_duplicates")
 	deleteFunc(crawl.Id, "pagereports")
 }
 
@@ -227,51 +319,165 @@ func (ds *CrawlRepository) DeleteProjectCrawls(p *models.Project) {
 	}
 }
 
-// Deletes all crawls that are unfinished and have the issues_end field set to null.
-// It cleans up the crawl data for each unfinished crawl before deleting it.
-func (ds *CrawlRepository) DeleteUnfinishedCrawls() {
+// FindUnfinishedCrawls returns a slice with all the crawls that have the issues_end field set to null.
+// These crawls were interrupted, usually because the server was stopped while they were running.
+func (ds *CrawlRepository) FindUnfinishedCrawls() []models.Crawl {
 	query := `
 		SELECT
-			crawls.id
+			crawls.id,
+			crawls.project_id,
+			projects.url,
+			crawls.start,
+			crawls.total_urls,
+			crawls.blocked_by_robotstxt,
+			crawls.noindex,
+			crawls.links_internal_follow,
+			crawls.links_internal_nofollow,
+			crawls.links_external_follow,
+			crawls.links_external_nofollow,
+			crawls.links_sponsored,
+			crawls.links_ugc,
+			crawls.depth_limited,
+			crawls.out_of_scope,
+			crawls.throttled,
+			crawls.mode
 		FROM crawls
+		INNER JOIN projects ON projects.id = crawls.project_id
 		WHERE crawls.issues_end IS NULL
 	`
-	count := 0
 
+	crawls := []models.Crawl{}
 	rows, err := ds.DB.Query(query)
 	if err != nil {
-		log.Println(err)
-		return
+		log.Printf("FindUnfinishedCrawls: %v\n", err)
+		return crawls
 	}
 
-	ids := []any{}
-	placeholders := []string{}
 	for rows.Next() {
-		c := &models.Crawl{}
-		err := rows.Scan(&c.Id)
+		crawl := models.Crawl{Crawling: true}
+		err := rows.Scan(
+			&crawl.Id,
+			&crawl.ProjectId,
+			&crawl.URL,
+			&crawl.Start,
+			&crawl.TotalURLs,
+			&crawl.BlockedByRobotstxt,
+			&crawl.Noindex,
+			&crawl.InternalFollowLinks,
+			&crawl.InternalNoFollowLinks,
+			&crawl.ExternalFollowLinks,
+			&crawl.ExternalNoFollowLinks,
+			&crawl.SponsoredLinks,
+			&crawl.UGCLinks,
+			&crawl.DepthLimited,
+			&crawl.OutOfScope,
+			&crawl.Throttled,
+			&crawl.Mode,
+		)
 		if err != nil {
-			log.Printf("DeleteUnfinishedCrawls: %v\n", err)
+			log.Printf("FindUnfinishedCrawls: %v\n", err)
 			continue
 		}
 
-		count++
-		ds.DeleteCrawlData(c)
-		ids = append(ids, c.Id)
-		placeholders = append(placeholders, "?")
+		crawls = append(crawls, crawl)
 	}
 
-	if len(ids) == 0 {
-		return
+	return crawls
+}
+
+// DeleteCrawl deletes the crawl and all of its associated data.
+func (ds *CrawlRepository) DeleteCrawl(c *models.Crawl) {
+	ds.DeleteCrawlData(c)
+
+	_, err := ds.DB.Exec("DELETE FROM crawls WHERE id = ?", c.Id)
+	if err != nil {
+		log.Printf("DeleteCrawl: cid %d %v\n", c.Id, err)
 	}
+}
 
-	placeholdersStr := strings.Join(placeholders, ",")
-	deleteQuery := fmt.Sprintf("DELETE FROM crawls WHERE id IN (%s)", placeholdersStr)
-	_, err = ds.DB.Exec(deleteQuery, ids...)
+// SaveCrawlCheckpoint stores the crawl's checkpoint data along with the id of the last
+// pagereport saved for the crawl. It also updates the crawl's counters so they are
+// consistent with the checkpoint if the crawl needs to be resumed.
+func (ds *CrawlRepository) SaveCrawlCheckpoint(crawl *models.Crawl, data []byte) error {
+	query := `UPDATE
+		crawls
+		SET
+			total_urls = ?,
+			blocked_by_robotstxt = ?,
+			noindex = ?,
+			links_internal_follow = ?,
+			links_internal_nofollow = ?,
+			links_external_follow = ?,
+			links_external_nofollow = ?,
+			links_sponsored = ?,
+			links_ugc = ?,
+			depth_limited = ?,
+			out_of_scope = ?,
+			throttled = ?
+		WHERE id = ?`
+
+	_, err := ds.DB.Exec(
+		query,
+		crawl.TotalURLs,
+		crawl.BlockedByRobotstxt,
+		crawl.Noindex,
+		crawl.InternalFollowLinks,
+		crawl.InternalNoFollowLinks,
+		crawl.ExternalFollowLinks,
+		crawl.ExternalNoFollowLinks,
+		crawl.SponsoredLinks,
+		crawl.UGCLinks,
+		crawl.DepthLimited,
+		crawl.OutOfScope,
+		crawl.Throttled,
+		crawl.Id,
+	)
 	if err != nil {
-		log.Printf("DeleteUnfinishedCrawls: %v", err)
+		return err
 	}
 
-	log.Printf("Deleted %d unfinished crawls.", count)
+	query = `
+		INSERT INTO crawl_checkpoints (crawl_id, last_pagereport_id, data)
+		SELECT ?, IFNULL(MAX(id), 0), ? FROM pagereports WHERE crawl_id = ?
+		ON DUPLICATE KEY UPDATE
+			last_pagereport_id = VALUES(last_pagereport_id),
+			data = VALUES(data)`
+
+	_, err = ds.DB.Exec(query, crawl.Id, data, crawl.Id)
+
+	return err
+}
+
+// FindCrawlCheckpoint returns the checkpoint data stored for the crawl.
+func (ds *CrawlRepository) FindCrawlCheckpoint(crawl *models.Crawl) ([]byte, error) {
+	query := `SELECT data FROM crawl_checkpoints WHERE crawl_id = ?`
+
+	var data []byte
+	err := ds.DB.QueryRow(query, crawl.Id).Scan(&data)
+
+	return data, err
+}
+
+// RevertToCrawlCheckpoint deletes the crawl's pagereports that were saved after the last
+// checkpoint. The pagereport's associated data is deleted by the foreign keys.
+func (ds *CrawlRepository) RevertToCrawlCheckpoint(crawl *models.Crawl) error {
+	query := `
+		DELETE FROM pagereports
+		WHERE crawl_id = ? AND id > (
+			SELECT last_pagereport_id FROM crawl_checkpoints WHERE crawl_id = ?
+		)`
+
+	_, err := ds.DB.Exec(query, crawl.Id, crawl.Id)
+
+	return err
+}
+
+// DeleteCrawlCheckpoint deletes the checkpoint data stored for the crawl.
+func (ds *CrawlRepository) DeleteCrawlCheckpoint(crawl *models.Crawl) {
+	_, err := ds.DB.Exec("DELETE FROM crawl_checkpoints WHERE crawl_id = ?", crawl.Id)
+	if err != nil {
+		log.Printf("DeleteCrawlCheckpoint: cid %d %v\n", crawl.Id, err)
+	}
 }
 
 // SaveIssuesCount stores the total number of issues as well as the total issues by priority for
@@ -293,6 +499,10 @@ func (ds *CrawlRepository) UpdateCrawl(crawl *models.Crawl) {
 			links_external_nofollow = ?,
 			links_sponsored = ?,
 			links_ugc = ?,
+			depth_limited = ?,
+			out_of_scope = ?,
+			throttled = ?,
+			login_failed = ?,
 			issues_end = ?,
 			critical_issues = ?,
 			alert_issues = ?,
@@ -315,6 +525,10 @@ func (ds *CrawlRepository) UpdateCrawl(crawl *models.Crawl) {
 		crawl.ExternalNoFollowLinks,
 		crawl.SponsoredLinks,
 		crawl.UGCLinks,
+		crawl.DepthLimited,
+		crawl.OutOfScope,
+		crawl.Throttled,
+		crawl.LoginFailed,
 		crawl.IssuesEnd,
 		crawl.CriticalIssues,
 		crawl.AlertIssues,
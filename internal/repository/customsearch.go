@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+	"math"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type CustomSearchRepository struct {
+	DB *sql.DB
+}
+
+// CountCustomSearchMatches returns the number of pages matching each of the custom search rules
+// in the crawl, mapped by the rule's name.
+func (ds *CustomSearchRepository) CountCustomSearchMatches(cid int64) map[string]int {
+	counts := map[string]int{}
+
+	rows, err := ds.DB.Query("SELECT name, COUNT(DISTINCT pagereport_id) FROM custom_search WHERE crawl_id = ? GROUP BY name", cid)
+	if err != nil {
+		log.Printf("CountCustomSearchMatches: %v\n", err)
+		return counts
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			log.Printf("CountCustomSearchMatches: %v\n", err)
+			continue
+		}
+
+		counts[name] = count
+	}
+
+	return counts
+}
+
+// GetNumberOfPagesForCustomSearch returns the number of pages of the paginator of the page
+// reports matching the named custom search rule.
+func (ds *CustomSearchRepository) GetNumberOfPagesForCustomSearch(cid int64, name string) int {
+	query := `
+		SELECT count(DISTINCT pagereport_id)
+		FROM custom_search
+		WHERE name = ? AND crawl_id = ?`
+
+	row := ds.DB.QueryRow(query, name, cid)
+	var c int
+	if err := row.Scan(&c); err != nil {
+		log.Printf("GetNumberOfPagesForCustomSearch: %v\n", err)
+	}
+	var f float64 = float64(c) / float64(paginationMax)
+	return int(math.Ceil(f))
+}
+
+// FindCustomSearchPageReports returns a slice of PageReports corresponding to the page specified in
+// the "p" parameter and matching the named custom search rule.
+func (ds *CustomSearchRepository) FindCustomSearchPageReports(cid int64, p int, name string) []models.PageReport {
+	max := paginationMax
+	offset := max * (p - 1)
+
+	query := `
+		SELECT
+			id,
+			url,
+			title
+		FROM pagereports
+		WHERE id IN (
+			SELECT DISTINCT pagereport_id
+			FROM custom_search
+			WHERE name = ? AND crawl_id = ?
+		) ORDER BY url ASC LIMIT ?, ?`
+
+	var pageReports []models.PageReport
+	rows, err := ds.DB.Query(query, name, cid, offset, max)
+	if err != nil {
+		log.Println(err)
+		return pageReports
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := models.PageReport{}
+		err := rows.Scan(&p.Id, &p.URL, &p.Title)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		pageReports = append(pageReports, p)
+	}
+
+	return pageReports
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"database/sql"
+	"fmt"
 	"log"
 	"sort"
+	"strings"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 )
@@ -223,3 +225,55 @@ func (ds *DashboardRepository) GetStatusCodeByDepth(cid int64) []models.StatusCo
 
 	return s
 }
+
+// GetTimingDistribution returns a slice of TimingDistribution models with the total number of
+// pagereports by duration range for each of the request phases. Pages where the phase took no
+// time are left out, for instance the DNS lookup of requests that reused a connection.
+func (ds *DashboardRepository) GetTimingDistribution(cid int64) []models.TimingDistribution {
+	phases := []struct {
+		name   string
+		column string
+	}{
+		{"DNS_LOOKUP", "dns_lookup"},
+		{"TCP_CONNECT", "tcp_connect"},
+		{"TLS_HANDSHAKE", "tls_handshake"},
+		{"TTFB", "ttfb"},
+		{"DOWNLOAD_TIME", "download"},
+	}
+
+	queries := []string{}
+	args := []interface{}{}
+	for _, p := range phases {
+		queries = append(queries, fmt.Sprintf(`
+		SELECT
+			'%[1]s',
+			COALESCE(SUM(CASE WHEN %[2]s < 100 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN %[2]s >= 100 AND %[2]s < 300 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN %[2]s >= 300 AND %[2]s < 800 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN %[2]s >= 800 AND %[2]s < 2000 THEN 1 ELSE 0 END), 0),
+			COALESCE(SUM(CASE WHEN %[2]s >= 2000 THEN 1 ELSE 0 END), 0)
+		FROM pagereports
+		WHERE crawl_id = ? AND crawled = 1 AND %[2]s > 0`, p.name, p.column))
+		args = append(args, cid)
+	}
+
+	d := []models.TimingDistribution{}
+
+	rows, err := ds.DB.Query(strings.Join(queries, " UNION ALL "), args...)
+	if err != nil {
+		log.Println(err)
+		return d
+	}
+
+	for rows.Next() {
+		t := models.TimingDistribution{}
+		err := rows.Scan(&t.Phase, &t.Under100, &t.Under300, &t.Under800, &t.Under2s, &t.Over2s)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		d = append(d, t)
+	}
+
+	return d
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"math"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type DiffRepository struct {
+	DB *sql.DB
+}
+
+// indexableSQL returns an SQL expression that is true if the pagereport with the specified
+// table alias is indexable, which means it is not blocked by robots.txt, has no noindex
+// attribute, has a 2xx status code and is not canonicalized to a different URL.
+func indexableSQL(t string) string {
+	return fmt.Sprintf(
+		"(%[1]s.noindex = 0 AND %[1]s.robotstxt_blocked = 0 AND %[1]s.status_code BETWEEN 200 AND 299 AND (IFNULL(%[1]s.canonical, '') = '' OR %[1]s.canonical = %[1]s.url))",
+		t,
+	)
+}
+
+// changedSQL returns the query for the URLs in both crawls where the specified value has changed.
+// The "a" alias is used for the older crawl's pagereports and "b" for the newer one's.
+func changedSQL(oldValue, newValue, where string) string {
+	return fmt.Sprintf(`
+		SELECT b.url AS url, %s AS old_value, %s AS new_value
+		FROM pagereports b
+		INNER JOIN pagereports a ON a.crawl_id = ? AND a.url_hash = b.url_hash
+		WHERE b.crawl_id = ? AND %s`, oldValue, newValue, where)
+}
+
+// diffQueries contains the queries for each type of change. All the queries select the url, old_value
+// and new_value columns and expect the older crawl id followed by the newer crawl id as parameters.
+var diffQueries = map[string]string{
+	models.DiffAdded: `
+		SELECT b.url AS url, '' AS old_value, CAST(b.status_code AS CHAR) AS new_value
+		FROM pagereports b
+		WHERE NOT EXISTS (SELECT 1 FROM pagereports a WHERE a.crawl_id = ? AND a.url_hash = b.url_hash)
+		AND b.crawl_id = ?`,
+	models.DiffRemoved: `
+		SELECT a.url AS url, CAST(a.status_code AS CHAR) AS old_value, '' AS new_value
+		FROM pagereports a
+		WHERE a.crawl_id = ?
+		AND NOT EXISTS (SELECT 1 FROM pagereports b WHERE b.crawl_id = ? AND b.url_hash = a.url_hash)`,
+	models.DiffStatusCode:  changedSQL("CAST(a.status_code AS CHAR)", "CAST(b.status_code AS CHAR)", "a.status_code <> b.status_code"),
+	models.DiffTitle:       changedSQL("IFNULL(a.title, '')", "IFNULL(b.title, '')", "NOT (a.title <=> b.title)"),
+	models.DiffDescription: changedSQL("IFNULL(a.description, '')", "IFNULL(b.description, '')", "NOT (a.description <=> b.description)"),
+	models.DiffH1:          changedSQL("IFNULL(a.h1, '')", "IFNULL(b.h1, '')", "NOT (a.h1 <=> b.h1)"),
+	models.DiffCanonical:   changedSQL("IFNULL(a.canonical, '')", "IFNULL(b.canonical, '')", "NOT (a.canonical <=> b.canonical)"),
+	models.DiffIndexability: changedSQL(
+		fmt.Sprintf("IF(%s, 'indexable', 'non-indexable')", indexableSQL("a")),
+		fmt.Sprintf("IF(%s, 'indexable', 'non-indexable')", indexableSQL("b")),
+		fmt.Sprintf("%s <> %s", indexableSQL("a"), indexableSQL("b")),
+	),
+}
+
+// diffIssuesSQL selects the issues of a crawl that don't exist for the same URL in a second crawl.
+// It expects the first crawl id followed by the second crawl id as parameters.
+const diffIssuesSQL = `
+	FROM issues ix
+	INNER JOIN pagereports px ON px.id = ix.pagereport_id
+	INNER JOIN issue_types ON issue_types.id = ix.issue_type_id
+	WHERE ix.crawl_id = ? AND NOT EXISTS (
+		SELECT 1
+		FROM issues iy
+		INNER JOIN pagereports py ON py.id = iy.pagereport_id
+		WHERE iy.crawl_id = ? AND iy.issue_type_id = ix.issue_type_id AND py.url_hash = px.url_hash
+	)`
+
+// CountDiffChanges returns the number of changes of the type "t" between two crawls.
+func (ds *DiffRepository) CountDiffChanges(from, to int64, t string) int {
+	query, ok := diffQueries[t]
+	if !ok {
+		return 0
+	}
+
+	row := ds.DB.QueryRow("SELECT count(*) FROM ("+query+") AS changes", from, to)
+	var c int
+	if err := row.Scan(&c); err != nil {
+		log.Printf("CountDiffChanges: %s %v\n", t, err)
+	}
+
+	return c
+}
+
+// GetNumberOfPagesForDiffChanges returns the number of pages needed to list the changes of type "t".
+func (ds *DiffRepository) GetNumberOfPagesForDiffChanges(from, to int64, t string) int {
+	c := ds.CountDiffChanges(from, to, t)
+	var f float64 = float64(c) / float64(paginationMax)
+
+	return int(math.Ceil(f))
+}
+
+// FindDiffChanges returns a slice with the changes of type "t" corresponding to the page "p".
+func (ds *DiffRepository) FindDiffChanges(from, to int64, t string, p int) []models.DiffChange {
+	changes := []models.DiffChange{}
+	query, ok := diffQueries[t]
+	if !ok {
+		return changes
+	}
+
+	max := paginationMax
+	offset := max * (p - 1)
+
+	rows, err := ds.DB.Query(query+" ORDER BY url ASC LIMIT ?, ?", from, to, offset, max)
+	if err != nil {
+		log.Printf("FindDiffChanges: %s %v\n", t, err)
+		return changes
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		c := models.DiffChange{Type: t}
+		if err := rows.Scan(&c.URL, &c.Old, &c.New); err != nil {
+			log.Printf("FindDiffChanges: %v\n", err)
+			continue
+		}
+
+		changes = append(changes, c)
+	}
+
+	return changes
+}
+
+// FindDiffIssues returns a slice with the number of issues introduced and resolved by issue type.
+func (ds *DiffRepository) FindDiffIssues(from, to int64) []models.DiffIssue {
+	issues := []models.DiffIssue{}
+	index := make(map[string]int)
+
+	query := `
+		SELECT
+			issue_types.type,
+			issue_types.priority,
+			count(*)
+		` + diffIssuesSQL + `
+		GROUP BY issue_types.type, issue_types.priority
+		ORDER BY issue_types.priority ASC`
+
+	count := func(x, y int64, introduced bool) {
+		rows, err := ds.DB.Query(query, x, y)
+		if err != nil {
+			log.Printf("FindDiffIssues: %v\n", err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			i := models.DiffIssue{}
+			var c int
+			if err := rows.Scan(&i.ErrorType, &i.Priority, &c); err != nil {
+				log.Printf("FindDiffIssues: %v\n", err)
+				continue
+			}
+
+			n, ok := index[i.ErrorType]
+			if !ok {
+				issues = append(issues, i)
+				n = len(issues) - 1
+				index[i.ErrorType] = n
+			}
+
+			if introduced {
+				issues[n].Introduced = c
+			} else {
+				issues[n].Resolved = c
+			}
+		}
+	}
+
+	count(to, from, true)
+	count(from, to, false)
+
+	return issues
+}
+
+// ExportDiffChanges sends all the changes of type "t" between two crawls through a read-only channel.
+func (ds *DiffRepository) ExportDiffChanges(from, to int64, t string) <-chan *models.DiffChange {
+	cStream := make(chan *models.DiffChange)
+
+	go func() {
+		defer close(cStream)
+
+		query, ok := diffQueries[t]
+		if !ok {
+			return
+		}
+
+		rows, err := ds.DB.Query(query+" ORDER BY url ASC", from, to)
+		if err != nil {
+			log.Printf("ExportDiffChanges: %s %v\n", t, err)
+			return
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			c := &models.DiffChange{Type: t}
+			if err := rows.Scan(&c.URL, &c.Old, &c.New); err != nil {
+				log.Printf("ExportDiffChanges: %v\n", err)
+				continue
+			}
+
+			cStream <- c
+		}
+	}()
+
+	return cStream
+}
+
+// ExportDiffIssues sends the URLs with introduced and resolved issues through a read-only channel.
+// The issue type is sent in the New field for introduced issues and in the Old field for resolved ones.
+func (ds *DiffRepository) ExportDiffIssues(from, to int64) <-chan *models.DiffChange {
+	cStream := make(chan *models.DiffChange)
+
+	query := `
+		SELECT
+			px.url,
+			issue_types.type
+		` + diffIssuesSQL + `
+		ORDER BY issue_types.priority ASC, px.url ASC`
+
+	go func() {
+		defer close(cStream)
+
+		send := func(x, y int64, t string) {
+			rows, err := ds.DB.Query(query, x, y)
+			if err != nil {
+				log.Printf("ExportDiffIssues: %v\n", err)
+				return
+			}
+			defer rows.Close()
+
+			for rows.Next() {
+				c := &models.DiffChange{Type: t}
+				var errorType string
+				if err := rows.Scan(&c.URL, &errorType); err != nil {
+					log.Printf("ExportDiffIssues: %v\n", err)
+					continue
+				}
+
+				if t == models.DiffIssueIntroduced {
+					c.New = errorType
+				} else {
+					c.Old = errorType
+				}
+
+				cStream <- c
+			}
+		}
+
+		send(to, from, models.DiffIssueIntroduced)
+		send(from, to, models.DiffIssueResolved)
+	}()
+
+	return cStream
+}
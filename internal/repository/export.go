@@ -331,6 +331,48 @@ func (ds *ExportRepository) ExportHreflangs(crawl *models.Crawl) <-chan *models.
 	return vStream
 }
 
+// Send all the redirect chain hops through a read-only channel, ordered by chain and position.
+func (ds *ExportRepository) ExportRedirectHops(crawl *models.Crawl) <-chan *models.ExportRedirectHop {
+	vStream := make(chan *models.ExportRedirectHop)
+
+	go func() {
+		defer close(vStream)
+
+		query := `
+			SELECT
+				pagereports.url,
+				redirect_hops.position,
+				redirect_hops.url,
+				redirect_hops.status_code,
+				redirect_hops.refresh,
+				redirect_hops.cross_scheme,
+				redirect_hops.cross_host
+			FROM redirect_hops
+			LEFT JOIN pagereports ON pagereports.id  = redirect_hops.pagereport_id
+			WHERE redirect_hops.crawl_id = ?
+			ORDER BY redirect_hops.pagereport_id, redirect_hops.position`
+
+		rows, err := ds.DB.Query(query, crawl.Id)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for rows.Next() {
+			v := &models.ExportRedirectHop{}
+			err := rows.Scan(&v.Origin, &v.Position, &v.URL, &v.StatusCode, &v.Refresh, &v.CrossScheme, &v.CrossHost)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
+
 // Export all issues by crawl through a read-only channel
 func (ds *ExportRepository) ExportIssues(crawl *models.Crawl) <-chan *models.ExportIssue {
 	vStream := make(chan *models.ExportIssue)
@@ -368,3 +410,130 @@ func (ds *ExportRepository) ExportIssues(crawl *models.Crawl) <-chan *models.Exp
 
 	return vStream
 }
+
+// Send all the cookies set by the crawled pages through a read-only channel, with one
+// row for each page setting the cookie.
+func (ds *ExportRepository) ExportCookies(crawl *models.Crawl) <-chan *models.ExportCookie {
+	vStream := make(chan *models.ExportCookie)
+
+	go func() {
+		defer close(vStream)
+
+		query := `
+			SELECT
+				pagereports.url,
+				cookies.name,
+				cookies.domain,
+				cookies.secure,
+				cookies.http_only,
+				cookies.same_site
+			FROM cookies
+			LEFT JOIN pagereports ON pagereports.id  = cookies.pagereport_id
+			WHERE cookies.crawl_id = ?
+			ORDER BY cookies.name, cookies.domain`
+
+		rows, err := ds.DB.Query(query, crawl.Id)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for rows.Next() {
+			v := &models.ExportCookie{}
+			err := rows.Scan(&v.Origin, &v.Name, &v.Domain, &v.Secure, &v.HttpOnly, &v.SameSite)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
+
+// Send all the scripts and stylesheets loaded by the crawled pages through a read-only channel.
+func (ds *ExportRepository) ExportPageResources(crawl *models.Crawl) <-chan *models.ExportResource {
+	vStream := make(chan *models.ExportResource)
+
+	go func() {
+		defer close(vStream)
+
+		query := `
+			SELECT
+				pagereports.url,
+				"script",
+				scripts.url
+			FROM scripts
+			LEFT JOIN pagereports ON pagereports.id  = scripts.pagereport_id
+			WHERE scripts.crawl_id = ?
+			UNION ALL
+			SELECT
+				pagereports.url,
+				"style",
+				styles.url
+			FROM styles
+			LEFT JOIN pagereports ON pagereports.id  = styles.pagereport_id
+			WHERE styles.crawl_id = ?`
+
+		rows, err := ds.DB.Query(query, crawl.Id, crawl.Id)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for rows.Next() {
+			v := &models.ExportResource{}
+			err := rows.Scan(&v.Origin, &v.Type, &v.URL)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
+
+// Send the structured data types of every page through a read-only channel, along with the
+// number of items found in the page and how many of them are not valid.
+func (ds *ExportRepository) ExportStructuredData(crawl *models.Crawl) <-chan *models.ExportStructuredData {
+	vStream := make(chan *models.ExportStructuredData)
+
+	go func() {
+		defer close(vStream)
+
+		query := `
+			SELECT
+				pagereports.url,
+				COALESCE(GROUP_CONCAT(DISTINCT NULLIF(structured_data.type, "") ORDER BY structured_data.type SEPARATOR ", "), ""),
+				COUNT(*),
+				SUM(structured_data.error <> "")
+			FROM structured_data
+			LEFT JOIN pagereports ON pagereports.id  = structured_data.pagereport_id
+			WHERE structured_data.crawl_id = ?
+			GROUP BY structured_data.pagereport_id, pagereports.url`
+
+		rows, err := ds.DB.Query(query, crawl.Id)
+		if err != nil {
+			log.Println(err)
+			return
+		}
+
+		for rows.Next() {
+			v := &models.ExportStructuredData{}
+			err := rows.Scan(&v.Origin, &v.Types, &v.Items, &v.Invalid)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			vStream <- v
+		}
+	}()
+
+	return vStream
+}
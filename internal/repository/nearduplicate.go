@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type NearDuplicateRepository struct {
+	DB *sql.DB
+}
+
+// FindFingerprintedPageReports returns the canonical html page reports of the crawl with a 20x
+// status code and a fingerprint of their main text. Only the fields needed to compare the
+// fingerprints are loaded.
+func (ds *NearDuplicateRepository) FindFingerprintedPageReports(cid int64) []models.PageReport {
+	pageReports := []models.PageReport{}
+	query := `
+		SELECT
+			id,
+			simhash,
+			body_hash
+		FROM pagereports
+		WHERE crawl_id = ? AND crawled = 1 AND media_type = "text/html" AND simhash <> 0
+		AND status_code >= 200 AND status_code < 300
+		AND (canonical = "" OR canonical = url)
+		ORDER BY id`
+
+	rows, err := ds.DB.Query(query, cid)
+	if err != nil {
+		log.Printf("FindFingerprintedPageReports: %v\n", err)
+		return pageReports
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := models.PageReport{}
+		err := rows.Scan(&p.Id, &p.SimHash, &p.BodyHash)
+		if err != nil {
+			log.Printf("FindFingerprintedPageReports: %v\n", err)
+			continue
+		}
+
+		pageReports = append(pageReports, p)
+	}
+
+	return pageReports
+}
+
+// SaveNearDuplicates saves the near-duplicate pages in batches.
+func (ds *NearDuplicateRepository) SaveNearDuplicates(cid int64, duplicates []models.NearDuplicate) error {
+	query := `
+		INSERT INTO near_duplicates (
+			pagereport_id,
+			crawl_id,
+			match_pagereport_id,
+			similarity,
+			cluster
+		) VALUES `
+	sqlString := ""
+	v := []interface{}{}
+
+	fn := func() error {
+		sqlString = sqlString[0 : len(sqlString)-1]
+		_, err := ds.DB.Exec(query+sqlString, v...)
+
+		v = []interface{}{}
+		sqlString = ""
+
+		return err
+	}
+
+	for _, d := range duplicates {
+		sqlString += "(?, ?, ?, ?, ?),"
+		v = append(v, d.PageReportId, cid, d.Match.Id, d.Similarity, d.Cluster)
+
+		if len(v) >= 800 {
+			if err := fn(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(v) > 0 {
+		return fn()
+	}
+
+	return nil
+}
+
+// FindNearDuplicate returns the closest match of the specified page report and the number of
+// pages in its cluster. The match has no id if the page report is not a near-duplicate.
+func (ds *NearDuplicateRepository) FindNearDuplicate(pageReport *models.PageReport, cid int64) models.NearDuplicate {
+	d := models.NearDuplicate{PageReportId: pageReport.Id}
+	query := `
+		SELECT
+			pagereports.id,
+			pagereports.url,
+			pagereports.title,
+			near_duplicates.similarity,
+			near_duplicates.cluster,
+			(SELECT COUNT(*) FROM near_duplicates c WHERE c.crawl_id = near_duplicates.crawl_id AND c.cluster = near_duplicates.cluster)
+		FROM near_duplicates
+		INNER JOIN pagereports ON pagereports.id = near_duplicates.match_pagereport_id
+		WHERE near_duplicates.pagereport_id = ? AND near_duplicates.crawl_id = ?`
+
+	row := ds.DB.QueryRow(query, pageReport.Id, cid)
+	err := row.Scan(&d.Match.Id, &d.Match.URL, &d.Match.Title, &d.Similarity, &d.Cluster, &d.ClusterSize)
+	if err != nil && err != sql.ErrNoRows {
+		log.Printf("FindNearDuplicate: %v\n", err)
+	}
+
+	return d
+}
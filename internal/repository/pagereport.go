@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"cmp"
 	"database/sql"
 	"log"
 	"math"
@@ -19,7 +20,7 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 	urlHash := Hash(r.URL)
 	var redirectHash string
 	if r.RedirectURL != "" {
-		redirectHash = Hash(r.RedirectURL)
+		redirectHash = Hash(cmp.Or(r.RedirectKey, r.RedirectURL))
 	}
 
 	query := `
@@ -49,9 +50,17 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 			in_sitemap,
 			depth,
 			body_hash,
-			ttfb
+			simhash,
+			ttfb,
+			attempts,
+			dns_lookup,
+			tcp_connect,
+			tls_handshake,
+			download,
+			protocol,
+			tls_version
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
 
 	stmt, err := ds.DB.Prepare(query)
 	if err != nil {
@@ -85,7 +94,15 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 		r.InSitemap,
 		r.Depth,
 		r.BodyHash,
+		r.SimHash,
 		r.TTFB,
+		r.Attempts,
+		r.DNSLookup,
+		r.TCPConnect,
+		r.TLSHandshake,
+		r.Download,
+		r.Protocol,
+		r.TLSVersion,
 	)
 	if err != nil {
 		return r, err
@@ -106,6 +123,11 @@ func (ds *PageReportRepository) SavePageReport(r *models.PageReport, cid int64)
 		ds.SavePageReportVideos,
 		ds.SavePageReportScripts,
 		ds.SavePageReportStyles,
+		ds.SavePageReportCookies,
+		ds.SavePageReportStructuredData,
+		ds.SavePageReportSocialTags,
+		ds.SavePageReportExtractions,
+		ds.SavePageReportCustomSearch,
 	}
 
 	for _, sf := range f {
@@ -175,7 +197,7 @@ func (ds *PageReportRepository) SavePageReportHreflangs(r *models.PageReport, ci
 	v := []interface{}{}
 	for _, h := range r.Hreflangs {
 		sqlString += "(?, ?, ?, ?, ?, ?, ?),"
-		v = append(v, r.Id, cid, r.Lang, h.URL, h.Lang, Hash(r.URL), Hash(h.URL))
+		v = append(v, r.Id, cid, r.Lang, h.URL, h.Lang, Hash(r.URL), Hash(cmp.Or(h.Key, h.URL)))
 	}
 	sqlString = sqlString[0 : len(sqlString)-1]
 	stmt, _ := ds.DB.Prepare(sqlString)
@@ -309,6 +331,112 @@ func (ds *PageReportRepository) SavePageReportStyles(r *models.PageReport, cid i
 	return err
 }
 
+// Save the cookies set by the pagereport.
+func (ds *PageReportRepository) SavePageReportCookies(r *models.PageReport, cid int64) error {
+	if len(r.Cookies) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO cookies (pagereport_id, crawl_id, name, domain, secure, http_only, same_site) values "
+	v := []interface{}{}
+	for _, c := range r.Cookies {
+		sqlString += "(?, ?, ?, ?, ?, ?, ?),"
+		v = append(v, r.Id, cid, c.Name, c.Domain, c.Secure, c.HttpOnly, c.SameSite)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
+// Save the structured data items of the pagereport.
+func (ds *PageReportRepository) SavePageReportStructuredData(r *models.PageReport, cid int64) error {
+	if len(r.StructuredData) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO structured_data (pagereport_id, crawl_id, format, type, raw, error) values "
+	v := []interface{}{}
+	for _, d := range r.StructuredData {
+		sqlString += "(?, ?, ?, ?, ?, ?),"
+		v = append(v, r.Id, cid, d.Format, d.Type, d.Raw, d.Error)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
+// Save the Open Graph and Twitter Card tags of the pagereport. The image URLs are hashed so
+// they can be joined with the crawled pagereports.
+func (ds *PageReportRepository) SavePageReportSocialTags(r *models.PageReport, cid int64) error {
+	if len(r.SocialTags) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO social_tags (pagereport_id, crawl_id, property, content, url, url_hash) values "
+	v := []interface{}{}
+	for _, t := range r.SocialTags {
+		hash := ""
+		if t.URL != "" {
+			hash = Hash(t.URL)
+		}
+
+		sqlString += "(?, ?, ?, ?, ?, ?),"
+		v = append(v, r.Id, cid, t.Property, Truncate(t.Content, 2048), Truncate(t.URL, 2048), hash)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
+// Save the values extracted from the pagereport with the project's custom extraction rules.
+func (ds *PageReportRepository) SavePageReportExtractions(r *models.PageReport, cid int64) error {
+	if len(r.Extractions) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO extractions (pagereport_id, crawl_id, name, value) values "
+	v := []interface{}{}
+	for _, e := range r.Extractions {
+		sqlString += "(?, ?, ?, ?),"
+		v = append(v, r.Id, cid, e.Name, Truncate(e.Value, 1024))
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
+// Save the names of the custom search rules matched by the pagereport.
+func (ds *PageReportRepository) SavePageReportCustomSearch(r *models.PageReport, cid int64) error {
+	if len(r.CustomSearch) == 0 {
+		return nil
+	}
+
+	sqlString := "INSERT INTO custom_search (pagereport_id, crawl_id, name) values "
+	v := []interface{}{}
+	for _, name := range r.CustomSearch {
+		sqlString += "(?, ?, ?),"
+		v = append(v, r.Id, cid, name)
+	}
+	sqlString = sqlString[0 : len(sqlString)-1]
+	stmt, _ := ds.DB.Prepare(sqlString)
+	defer stmt.Close()
+
+	_, err := stmt.Exec(v...)
+	return err
+}
+
 // FindAllPageReportsByCrawlId returns a channel where it streams all the crawl's page reports.
 // Once it is done it closes the channel.
 func (ds *PageReportRepository) FindAllPageReportsByCrawlId(cid int64) <-chan *models.PageReport {
@@ -341,7 +469,14 @@ func (ds *PageReportRepository) FindAllPageReportsByCrawlId(cid int64) <-chan *m
 				in_sitemap,
 				depth,
 				body_hash,
-				ttfb
+				ttfb,
+				attempts,
+				dns_lookup,
+				tcp_connect,
+				tls_handshake,
+				download,
+				protocol,
+				tls_version
 			FROM pagereports
 			WHERE crawl_id = ?`
 
@@ -375,6 +510,13 @@ func (ds *PageReportRepository) FindAllPageReportsByCrawlId(cid int64) <-chan *m
 				&p.Depth,
 				&p.BodyHash,
 				&p.TTFB,
+				&p.Attempts,
+				&p.DNSLookup,
+				&p.TCPConnect,
+				&p.TLSHandshake,
+				&p.Download,
+				&p.Protocol,
+				&p.TLSVersion,
 			)
 			if err != nil {
 				log.Println(err)
@@ -420,7 +562,14 @@ func (ds *PageReportRepository) FindAllPageReportsByCrawlIdAndErrorType(cid int6
 				in_sitemap,
 				depth,
 				body_hash,
-				ttfb
+				ttfb,
+				attempts,
+				dns_lookup,
+				tcp_connect,
+				tls_handshake,
+				download,
+				protocol,
+				tls_version
 			FROM pagereports
 			WHERE crawl_id = ?
 			AND id IN (
@@ -461,6 +610,112 @@ func (ds *PageReportRepository) FindAllPageReportsByCrawlIdAndErrorType(cid int6
 				&p.Depth,
 				&p.BodyHash,
 				&p.TTFB,
+				&p.Attempts,
+				&p.DNSLookup,
+				&p.TCPConnect,
+				&p.TLSHandshake,
+				&p.Download,
+				&p.Protocol,
+				&p.TLSVersion,
+			)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			prStream <- p
+		}
+	}()
+
+	return prStream
+}
+
+// FindAllPageReportsByCrawlIdAndCustomSearch returns a channel of pagereports where it streams all the reports
+// for the specified crawl matching the named custom search rule. Once it is done it closes the channel.
+func (ds *PageReportRepository) FindAllPageReportsByCrawlIdAndCustomSearch(cid int64, name string) <-chan *models.PageReport {
+	prStream := make(chan *models.PageReport)
+
+	go func() {
+		defer close(prStream)
+
+		query := `
+			SELECT
+				id,
+				url,
+				redirect_url,
+				refresh,
+				status_code,
+				content_type,
+				media_type,
+				lang,
+				title,
+				description,
+				robots,
+				noindex,
+				canonical,
+				h1,
+				h2,
+				words,
+				size,
+				robotstxt_blocked,
+				crawled,
+				in_sitemap,
+				depth,
+				body_hash,
+				ttfb,
+				attempts,
+				dns_lookup,
+				tcp_connect,
+				tls_handshake,
+				download,
+				protocol,
+				tls_version
+			FROM pagereports
+			WHERE crawl_id = ?
+			AND id IN (
+				SELECT
+					pagereport_id
+				FROM custom_search
+				WHERE name = ? AND crawl_id = ?
+			)`
+
+		rows, err := ds.DB.Query(query, cid, name, cid)
+		if err != nil {
+			log.Println(err)
+		}
+
+		for rows.Next() {
+			p := &models.PageReport{}
+			err := rows.Scan(&p.Id,
+				&p.URL,
+				&p.RedirectURL,
+				&p.Refresh,
+				&p.StatusCode,
+				&p.ContentType,
+				&p.MediaType,
+				&p.Lang,
+				&p.Title,
+				&p.Description,
+				&p.Robots,
+				&p.Noindex,
+				&p.Canonical,
+				&p.H1,
+				&p.H2,
+				&p.Words,
+				&p.Size,
+				&p.BlockedByRobotstxt,
+				&p.Crawled,
+				&p.InSitemap,
+				&p.Depth,
+				&p.BodyHash,
+				&p.TTFB,
+				&p.Attempts,
+				&p.DNSLookup,
+				&p.TCPConnect,
+				&p.TLSHandshake,
+				&p.Download,
+				&p.Protocol,
+				&p.TLSVersion,
 			)
 			if err != nil {
 				log.Println(err)
@@ -501,7 +756,14 @@ func (ds *PageReportRepository) FindPageReportById(rid int) models.PageReport {
 			in_sitemap,
 			depth,
 			body_hash,
-			ttfb
+			ttfb,
+			attempts,
+			dns_lookup,
+			tcp_connect,
+			tls_handshake,
+			download,
+			protocol,
+			tls_version
 		FROM pagereports
 		WHERE id = ?`
 
@@ -532,6 +794,13 @@ func (ds *PageReportRepository) FindPageReportById(rid int) models.PageReport {
 		&p.Depth,
 		&p.BodyHash,
 		&p.TTFB,
+		&p.Attempts,
+		&p.DNSLookup,
+		&p.TCPConnect,
+		&p.TLSHandshake,
+		&p.Download,
+		&p.Protocol,
+		&p.TLSVersion,
 	)
 	if err != nil {
 		log.Println(err)
@@ -706,6 +975,103 @@ func (ds *PageReportRepository) FindPageReportStyles(pageReport *models.PageRepo
 	return styles
 }
 
+// Find the structured data items of an specific pagereport.
+func (ds *PageReportRepository) FindPageReportStructuredData(pageReport *models.PageReport, cid int64) []models.StructuredData {
+	items := []models.StructuredData{}
+
+	rows, err := ds.DB.Query("SELECT format, type, raw, error FROM structured_data WHERE pagereport_id = ?", pageReport.Id)
+	if err != nil {
+		log.Println(err)
+		return items
+	}
+
+	for rows.Next() {
+		d := models.StructuredData{}
+		err = rows.Scan(&d.Format, &d.Type, &d.Raw, &d.Error)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		items = append(items, d)
+	}
+
+	return items
+}
+
+// Find the Open Graph and Twitter Card tags of an specific pagereport.
+func (ds *PageReportRepository) FindPageReportSocialTags(pageReport *models.PageReport, cid int64) []models.SocialTag {
+	tags := []models.SocialTag{}
+
+	rows, err := ds.DB.Query("SELECT property, content, url FROM social_tags WHERE pagereport_id = ? ORDER BY id", pageReport.Id)
+	if err != nil {
+		log.Println(err)
+		return tags
+	}
+
+	for rows.Next() {
+		t := models.SocialTag{}
+		err = rows.Scan(&t.Property, &t.Content, &t.URL)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		tags = append(tags, t)
+	}
+
+	return tags
+}
+
+// Find the values extracted from an specific pagereport with the custom extraction rules.
+func (ds *PageReportRepository) FindPageReportExtractions(pageReport *models.PageReport, cid int64) []models.Extraction {
+	extractions := []models.Extraction{}
+
+	rows, err := ds.DB.Query("SELECT name, value FROM extractions WHERE pagereport_id = ? ORDER BY id", pageReport.Id)
+	if err != nil {
+		log.Println(err)
+		return extractions
+	}
+
+	for rows.Next() {
+		e := models.Extraction{}
+		err = rows.Scan(&e.Name, &e.Value)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		extractions = append(extractions, e)
+	}
+
+	return extractions
+}
+
+// FindExtractionNames returns the names of the custom extraction rules that extracted any value
+// in the crawl, in the order they were first found.
+func (ds *PageReportRepository) FindExtractionNames(cid int64) []string {
+	names := []string{}
+
+	rows, err := ds.DB.Query("SELECT name FROM extractions WHERE crawl_id = ? GROUP BY name ORDER BY MIN(id)", cid)
+	if err != nil {
+		log.Println(err)
+		return names
+	}
+
+	for rows.Next() {
+		var name string
+		err = rows.Scan(&name)
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		names = append(names, name)
+	}
+
+	return names
+}
+
 // FindLinks returns a slice of paginated InternalLinks. The page is specified in the "p" parameter.
 func (ds *PageReportRepository) FindLinks(pageReport *models.PageReport, cid int64, p int) []models.InternalLink {
 	max := paginationMax
@@ -3,6 +3,7 @@ package repository
 import (
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 )
@@ -11,6 +12,107 @@ type ProjectRepository struct {
 	DB *sql.DB
 }
 
+// projectColumns is the list of columns scanned by scanProject.
+const projectColumns = `
+	id,
+	url,
+	ignore_robotstxt,
+	follow_nofollow,
+	include_noindex,
+	crawl_sitemap,
+	allow_subdomains,
+	basic_auth,
+	deleting,
+	created,
+	check_external_links,
+	archive,
+	user_agent,
+	crawl_retention,
+	crawl_schedule,
+	next_crawl,
+	crawl_workers,
+	crawl_min_delay,
+	crawl_max_delay,
+	crawl_max_rps,
+	crawl_timeout,
+	crawl_limit,
+	max_depth,
+	include_rules,
+	exclude_rules,
+	ignored_params,
+	remove_index_documents,
+	custom_headers,
+	custom_cookies,
+	login_url,
+	login_user_field,
+	login_pass_field,
+	login_user,
+	login_success,
+	login_logged_out,
+	proxy,
+	request_timeout,
+	request_retries,
+	extractors,
+	custom_search`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanProject scans a row selected with the projectColumns into a Project model.
+func scanProject(s scanner) (models.Project, error) {
+	p := models.Project{}
+	var nextCrawl sql.NullTime
+	err := s.Scan(
+		&p.Id,
+		&p.URL,
+		&p.IgnoreRobotsTxt,
+		&p.FollowNofollow,
+		&p.IncludeNoindex,
+		&p.CrawlSitemap,
+		&p.AllowSubdomains,
+		&p.BasicAuth,
+		&p.Deleting,
+		&p.Created,
+		&p.CheckExternalLinks,
+		&p.Archive,
+		&p.UserAgent,
+		&p.CrawlRetention,
+		&p.CrawlSchedule,
+		&nextCrawl,
+		&p.CrawlWorkers,
+		&p.CrawlMinDelay,
+		&p.CrawlMaxDelay,
+		&p.CrawlMaxRPS,
+		&p.CrawlTimeout,
+		&p.CrawlLimit,
+		&p.MaxDepth,
+		&p.IncludeRules,
+		&p.ExcludeRules,
+		&p.IgnoredParams,
+		&p.RemoveIndex,
+		&p.CustomHeaders,
+		&p.CustomCookies,
+		&p.LoginURL,
+		&p.LoginUserField,
+		&p.LoginPassField,
+		&p.LoginUser,
+		&p.LoginSuccess,
+		&p.LoginLoggedOut,
+		&p.Proxy,
+		&p.RequestTimeout,
+		&p.RequestRetries,
+		&p.Extractors,
+		&p.CustomSearch,
+	)
+
+	if nextCrawl.Valid {
+		p.NextCrawl = nextCrawl.Time
+	}
+
+	return p, err
+}
+
 // SaveProject inserts a new project into the database.
 func (ds *ProjectRepository) SaveProject(project *models.Project, uid int) {
 	query := `
@@ -25,9 +127,36 @@ func (ds *ProjectRepository) SaveProject(project *models.Project, uid int) {
 			user_id,
 			check_external_links,
 			archive,
-			user_agent
+			user_agent,
+			crawl_retention,
+			crawl_schedule,
+			next_crawl,
+			crawl_workers,
+			crawl_min_delay,
+			crawl_max_delay,
+			crawl_max_rps,
+			crawl_timeout,
+			crawl_limit,
+			max_depth,
+			include_rules,
+			exclude_rules,
+			ignored_params,
+			remove_index_documents,
+			custom_headers,
+			custom_cookies,
+			login_url,
+			login_user_field,
+			login_pass_field,
+			login_user,
+			login_success,
+			login_logged_out,
+			proxy,
+			request_timeout,
+			request_retries,
+			extractors,
+			custom_search
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	stmt, _ := ds.DB.Prepare(query)
@@ -44,6 +173,33 @@ func (ds *ProjectRepository) SaveProject(project *models.Project, uid int) {
 		project.CheckExternalLinks,
 		project.Archive,
 		project.UserAgent,
+		project.CrawlRetention,
+		project.CrawlSchedule,
+		nullTime(project.NextCrawl),
+		project.CrawlWorkers,
+		project.CrawlMinDelay,
+		project.CrawlMaxDelay,
+		project.CrawlMaxRPS,
+		project.CrawlTimeout,
+		project.CrawlLimit,
+		project.MaxDepth,
+		project.IncludeRules,
+		project.ExcludeRules,
+		project.IgnoredParams,
+		project.RemoveIndex,
+		project.CustomHeaders,
+		project.CustomCookies,
+		project.LoginURL,
+		project.LoginUserField,
+		project.LoginPassField,
+		project.LoginUser,
+		project.LoginSuccess,
+		project.LoginLoggedOut,
+		project.Proxy,
+		project.RequestTimeout,
+		project.RequestRetries,
+		project.Extractors,
+		project.CustomSearch,
 	)
 	if err != nil {
 		log.Printf("saveProject: %v\n", err)
@@ -54,20 +210,7 @@ func (ds *ProjectRepository) SaveProject(project *models.Project, uid int) {
 func (ds *ProjectRepository) FindProjectsByUser(uid int) []models.Project {
 	var projects []models.Project
 	query := `
-		SELECT
-			id,
-			url,
-			ignore_robotstxt,
-			follow_nofollow,
-			include_noindex,
-			crawl_sitemap,
-			allow_subdomains,
-			basic_auth,
-			deleting,
-			created,
-			check_external_links,
-			archive,
-			user_agent
+		SELECT ` + projectColumns + `
 		FROM projects
 		WHERE user_id = ?
 		ORDER BY url ASC`
@@ -79,22 +222,7 @@ func (ds *ProjectRepository) FindProjectsByUser(uid int) []models.Project {
 	}
 
 	for rows.Next() {
-		p := models.Project{}
-		err := rows.Scan(
-			&p.Id,
-			&p.URL,
-			&p.IgnoreRobotsTxt,
-			&p.FollowNofollow,
-			&p.IncludeNoindex,
-			&p.CrawlSitemap,
-			&p.AllowSubdomains,
-			&p.BasicAuth,
-			&p.Deleting,
-			&p.Created,
-			&p.CheckExternalLinks,
-			&p.Archive,
-			&p.UserAgent,
-		)
+		p, err := scanProject(rows)
 		if err != nil {
 			log.Println(err)
 			continue
@@ -109,41 +237,13 @@ func (ds *ProjectRepository) FindProjectsByUser(uid int) []models.Project {
 // Returns a Project model with the speciefied id and user id.
 func (ds *ProjectRepository) FindProjectById(id int, uid int) (models.Project, error) {
 	query := `
-		SELECT
-			id,
-			url,
-			ignore_robotstxt,
-			follow_nofollow,
-			include_noindex,
-			crawl_sitemap,
-			allow_subdomains,
-			basic_auth,
-			deleting,
-			created,
-			check_external_links,
-			archive,
-			user_agent
+		SELECT ` + projectColumns + `
 		FROM projects
 		WHERE id = ? AND user_id = ?`
 
 	row := ds.DB.QueryRow(query, id, uid)
 
-	p := models.Project{}
-	err := row.Scan(
-		&p.Id,
-		&p.URL,
-		&p.IgnoreRobotsTxt,
-		&p.FollowNofollow,
-		&p.IncludeNoindex,
-		&p.CrawlSitemap,
-		&p.AllowSubdomains,
-		&p.BasicAuth,
-		&p.Deleting,
-		&p.Created,
-		&p.CheckExternalLinks,
-		&p.Archive,
-		&p.UserAgent,
-	)
+	p, err := scanProject(row)
 	if err != nil {
 		log.Println(err)
 		return p, err
@@ -152,6 +252,25 @@ func (ds *ProjectRepository) FindProjectById(id int, uid int) (models.Project, e
 	return p, nil
 }
 
+// FindProject returns the Project model with the specified id regardless of its user.
+// It is meant to be used by background processes that don't have a user session.
+func (ds *ProjectRepository) FindProject(id int64) (models.Project, error) {
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE id = ?`
+
+	row := ds.DB.QueryRow(query, id)
+
+	p, err := scanProject(row)
+	if err != nil {
+		log.Printf("FindProject: pid %d %v\n", id, err)
+		return p, err
+	}
+
+	return p, nil
+}
+
 // DisableProject disables a project marking it as "deleting".
 func (ds *ProjectRepository) DisableProject(p *models.Project) {
 	query := `UPDATE projects SET deleting=1 WHERE id = ?`
@@ -183,7 +302,34 @@ func (ds *ProjectRepository) UpdateProject(p *models.Project) error {
 			basic_auth = ?,
 			check_external_links = ?,
 			archive = ?,
-			user_agent = ?
+			user_agent = ?,
+			crawl_retention = ?,
+			crawl_schedule = ?,
+			next_crawl = ?,
+			crawl_workers = ?,
+			crawl_min_delay = ?,
+			crawl_max_delay = ?,
+			crawl_max_rps = ?,
+			crawl_timeout = ?,
+			crawl_limit = ?,
+			max_depth = ?,
+			include_rules = ?,
+			exclude_rules = ?,
+			ignored_params = ?,
+			remove_index_documents = ?,
+			custom_headers = ?,
+			custom_cookies = ?,
+			login_url = ?,
+			login_user_field = ?,
+			login_pass_field = ?,
+			login_user = ?,
+			login_success = ?,
+			login_logged_out = ?,
+			proxy = ?,
+			request_timeout = ?,
+			request_retries = ?,
+			extractors = ?,
+			custom_search = ?
 		WHERE id = ?
 	`
 	_, err := ds.DB.Exec(
@@ -197,8 +343,77 @@ func (ds *ProjectRepository) UpdateProject(p *models.Project) error {
 		p.CheckExternalLinks,
 		p.Archive,
 		p.UserAgent,
+		p.CrawlRetention,
+		p.CrawlSchedule,
+		nullTime(p.NextCrawl),
+		p.CrawlWorkers,
+		p.CrawlMinDelay,
+		p.CrawlMaxDelay,
+		p.CrawlMaxRPS,
+		p.CrawlTimeout,
+		p.CrawlLimit,
+		p.MaxDepth,
+		p.IncludeRules,
+		p.ExcludeRules,
+		p.IgnoredParams,
+		p.RemoveIndex,
+		p.CustomHeaders,
+		p.CustomCookies,
+		p.LoginURL,
+		p.LoginUserField,
+		p.LoginPassField,
+		p.LoginUser,
+		p.LoginSuccess,
+		p.LoginLoggedOut,
+		p.Proxy,
+		p.RequestTimeout,
+		p.RequestRetries,
+		p.Extractors,
+		p.CustomSearch,
 		p.Id,
 	)
 
 	return err
 }
+
+// FindScheduledProjects returns the projects with a scheduled crawl due before the specified time.
+// Projects that are being deleted are not included.
+func (ds *ProjectRepository) FindScheduledProjects(before time.Time) []models.Project {
+	projects := []models.Project{}
+	query := `
+		SELECT ` + projectColumns + `
+		FROM projects
+		WHERE crawl_schedule <> '' AND next_crawl IS NOT NULL AND next_crawl <= ? AND deleting = 0`
+
+	rows, err := ds.DB.Query(query, before)
+	if err != nil {
+		log.Printf("FindScheduledProjects: %v\n", err)
+		return projects
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			log.Printf("FindScheduledProjects: %v\n", err)
+			continue
+		}
+
+		projects = append(projects, p)
+	}
+
+	return projects
+}
+
+// UpdateNextCrawl updates the time of the project's next scheduled crawl.
+func (ds *ProjectRepository) UpdateNextCrawl(p *models.Project) error {
+	query := `UPDATE projects SET next_crawl = ? WHERE id = ?`
+	_, err := ds.DB.Exec(query, nullTime(p.NextCrawl), p.Id)
+
+	return err
+}
+
+// nullTime returns a NullTime that is not valid if the time is zero.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
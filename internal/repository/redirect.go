@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"database/sql"
+	"log"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type RedirectRepository struct {
+	DB *sql.DB
+}
+
+// FindRedirectPageReports returns the crawled page reports that redirect to another URL, as well
+// as the ones that are the target of a redirect. Only the fields needed to follow the redirects
+// are loaded.
+func (ds *RedirectRepository) FindRedirectPageReports(cid int64) []models.PageReport {
+	pageReports := []models.PageReport{}
+	query := `
+		SELECT
+			id,
+			url,
+			status_code,
+			redirect_url,
+			refresh
+		FROM pagereports
+		WHERE crawl_id = ? AND crawled = 1 AND (
+			redirect_hash != "" OR url_hash IN (
+				SELECT redirect_hash FROM pagereports WHERE crawl_id = ? AND redirect_hash != ""
+			)
+		)`
+
+	rows, err := ds.DB.Query(query, cid, cid)
+	if err != nil {
+		log.Printf("FindRedirectPageReports: %v\n", err)
+		return pageReports
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		p := models.PageReport{}
+		err := rows.Scan(&p.Id, &p.URL, &p.StatusCode, &p.RedirectURL, &p.Refresh)
+		if err != nil {
+			log.Printf("FindRedirectPageReports: %v\n", err)
+			continue
+		}
+
+		pageReports = append(pageReports, p)
+	}
+
+	return pageReports
+}
+
+// SaveRedirectChains saves the hops of the redirect chains in batches.
+func (ds *RedirectRepository) SaveRedirectChains(cid int64, chains []models.RedirectChain) error {
+	query := `
+		INSERT INTO redirect_hops (
+			pagereport_id,
+			crawl_id,
+			position,
+			url,
+			status_code,
+			refresh,
+			cross_scheme,
+			cross_host
+		) VALUES `
+	sqlString := ""
+	v := []interface{}{}
+
+	fn := func() error {
+		sqlString = sqlString[0 : len(sqlString)-1]
+		_, err := ds.DB.Exec(query+sqlString, v...)
+
+		v = []interface{}{}
+		sqlString = ""
+
+		return err
+	}
+
+	for _, c := range chains {
+		for i, h := range c.Hops {
+			sqlString += "(?, ?, ?, ?, ?, ?, ?, ?),"
+			v = append(v, c.PageReportId, cid, i, h.URL, h.StatusCode, h.Refresh, h.CrossScheme, h.CrossHost)
+
+			if len(v) >= 800 {
+				if err := fn(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(v) > 0 {
+		return fn()
+	}
+
+	return nil
+}
+
+// FindRedirectChain returns the redirect chain that starts in the specified page report.
+// The chain has no hops if the page report doesn't redirect.
+func (ds *RedirectRepository) FindRedirectChain(pageReport *models.PageReport, cid int64) models.RedirectChain {
+	chain := models.RedirectChain{PageReportId: pageReport.Id, Hops: []models.RedirectHop{}}
+	query := `
+		SELECT
+			url,
+			status_code,
+			refresh,
+			cross_scheme,
+			cross_host
+		FROM redirect_hops
+		WHERE pagereport_id = ? AND crawl_id = ?
+		ORDER BY position`
+
+	rows, err := ds.DB.Query(query, pageReport.Id, cid)
+	if err != nil {
+		log.Printf("FindRedirectChain: %v\n", err)
+		return chain
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		h := models.RedirectHop{}
+		err := rows.Scan(&h.URL, &h.StatusCode, &h.Refresh, &h.CrossScheme, &h.CrossHost)
+		if err != nil {
+			log.Printf("FindRedirectChain: %v\n", err)
+			continue
+		}
+
+		chain.Hops = append(chain.Hops, h)
+	}
+
+	return chain
+}
@@ -34,12 +34,19 @@ func NewServer(container *services.Container) {
 	http.HandleFunc("GET /crawl/live", container.CookieSession.Auth(crawlHandler.liveCrawlHandler))
 	http.HandleFunc("GET /crawl/auth", container.CookieSession.Auth(crawlHandler.authGetHandler))
 	http.HandleFunc("POST /crawl/auth", container.CookieSession.Auth(crawlHandler.authPostHandler))
+	http.HandleFunc("GET /crawl/list", container.CookieSession.Auth(crawlHandler.listGetHandler))
+	http.HandleFunc("POST /crawl/list", container.CookieSession.Auth(crawlHandler.listPostHandler))
 	http.HandleFunc("GET /crawl/ws", container.CookieSession.Auth(crawlHandler.wsHandler))
 
 	// Dashboard route
 	dashboardHandler := dashboardHandler{container}
 	http.HandleFunc("GET /dashboard", container.CookieSession.Auth(dashboardHandler.indexHandler))
 
+	// Crawl diff routes
+	diffHandler := diffHandler{container}
+	http.HandleFunc("GET /diff", container.CookieSession.Auth(diffHandler.indexHandler))
+	http.HandleFunc("GET /diff/export", container.CookieSession.Auth(diffHandler.exportHandler))
+
 	// URL explorer route
 	explorerHandler := explorerHandler{container}
 	http.HandleFunc("GET /explorer", container.CookieSession.Auth(explorerHandler.indexHandler))
@@ -66,6 +73,11 @@ func NewServer(container *services.Container) {
 	http.HandleFunc("POST /project/edit", container.CookieSession.Auth(projectHandler.editPostHandler))
 	http.HandleFunc("GET /project/delete", container.CookieSession.Auth(projectHandler.deleteHandler))
 
+	// Custom search routes
+	searchHandler := searchHandler{container}
+	http.HandleFunc("GET /search", container.CookieSession.Auth(searchHandler.indexHandler))
+	http.HandleFunc("GET /search/view", container.CookieSession.Auth(searchHandler.viewHandler))
+
 	// Resource route
 	resourceHandler := resourceHandler{container}
 	http.HandleFunc("GET /resources", container.CookieSession.Auth(resourceHandler.indexHandler))
@@ -42,7 +42,12 @@ func (h *archiveHandler) archiveHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
@@ -112,7 +117,12 @@ func (h *archiveHandler) downloadHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
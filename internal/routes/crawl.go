@@ -2,11 +2,14 @@ package routes
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +23,9 @@ const (
 	writeWait  = 10 * time.Second
 	pongWait   = 60 * time.Second
 	pingPeriod = (pongWait * 9) / 10
+
+	// Max size of the uploaded list of URLs.
+	maxURLListSize = 10 << 20
 )
 
 type crawlHandler struct {
@@ -28,7 +34,7 @@ type crawlHandler struct {
 
 // startHandler handles the crawling of a project.
 // It expects a query parameter "pid" containing the project id to be crawled.
-// In case the project requieres BasicAuth it will redirect the user to the BasicAuth
+// In case the project requieres BasicAuth or form login it will redirect the user to the
 // credentials URL. Otherwise, it starts a new crawler and redirects to the live crawling page.
 func (h *crawlHandler) startHandler(w http.ResponseWriter, r *http.Request) {
 	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
@@ -49,8 +55,8 @@ func (h *crawlHandler) startHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if p.BasicAuth {
-		http.Redirect(w, r, "/crawl/auth?id="+strconv.Itoa(pid), http.StatusSeeOther)
+	if p.BasicAuth || p.LoginURL != "" {
+		http.Redirect(w, r, "/crawl/auth?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 		return
 	}
 
@@ -101,9 +107,10 @@ func (h *crawlHandler) stopHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 }
 
-// handleCrawlAuth handles the crawling of a project with BasicAuth.
+// handleCrawlAuth handles the crawling of a project with BasicAuth or form login.
 // It expects a query parameter "pid" containing the project id to be crawled.
-// A form will be presented to the user to input the BasicAuth credentials.
+// A form will be presented to the user to input the BasicAuth credentials and the
+// form login password.
 // This handler handles the GET request.
 func (h *crawlHandler) authGetHandler(w http.ResponseWriter, r *http.Request) {
 	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
@@ -134,7 +141,7 @@ func (h *crawlHandler) authGetHandler(w http.ResponseWriter, r *http.Request) {
 	h.Renderer.RenderTemplate(w, "crawl_auth", pageView, user.Lang)
 }
 
-// Handle the BasicAuth form. Once it is submitted a crawler with BasicAuth is started.
+// Handle the credentials form. Once it is submitted a crawler with the credentials is started.
 // It processes the auth form data and starts the crawler.
 // This handler handles the POST request and redirects to the live crawl page.
 func (h *crawlHandler) authPostHandler(w http.ResponseWriter, r *http.Request) {
@@ -163,8 +170,9 @@ func (h *crawlHandler) authPostHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	basicAuth := models.BasicAuth{
-		AuthUser: r.FormValue("username"),
-		AuthPass: r.FormValue("password"),
+		AuthUser:  r.FormValue("username"),
+		AuthPass:  r.FormValue("password"),
+		LoginPass: r.FormValue("login_pass"),
 	}
 
 	err = h.CrawlerService.StartCrawler(p, basicAuth)
@@ -177,6 +185,126 @@ func (h *crawlHandler) authPostHandler(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
 }
 
+// listGetHandler displays the form to crawl a list of URLs.
+// It expects a query parameter "pid" containing the project id to be crawled.
+// This handler handles the GET request.
+func (h *crawlHandler) listGetHandler(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	p, err := h.ProjectService.FindProject(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	pageView := &PageView{
+		Lang:      user.Lang,
+		Theme:     user.Theme,
+		User:      *user,
+		PageTitle: "CRAWL_LIST_PAGE_TITLE",
+		Data: struct {
+			Project models.Project
+			Error   bool
+		}{Project: p},
+	}
+
+	h.Renderer.RenderTemplate(w, "crawl_list", pageView, user.Lang)
+}
+
+// listPostHandler handles the list crawl form. The URLs are read from the uploaded file or
+// from the text area if no file is uploaded. Once the list is parsed a list mode crawler is
+// started and the user is redirected to the live crawl page.
+// This handler handles the POST request.
+func (h *crawlHandler) listPostHandler(w http.ResponseWriter, r *http.Request) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	p, err := h.ProjectService.FindProject(pid, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxURLListSize)
+	err = r.ParseMultipartForm(maxURLListSize)
+	if err != nil {
+		log.Printf("crawl list ParseMultipartForm: %v\n", err)
+		http.Redirect(w, r, "/crawl/list?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+		return
+	}
+
+	var list io.Reader = strings.NewReader(r.FormValue("urls"))
+	file, _, err := r.FormFile("url_list")
+	if err == nil {
+		defer file.Close()
+		list = file
+	}
+
+	limit := p.CrawlLimit
+	if limit == 0 {
+		limit = services.CrawlLimit
+	}
+
+	followRedirects, err := strconv.ParseBool(r.FormValue("follow_redirects"))
+	if err != nil {
+		followRedirects = false
+	}
+
+	basicAuth := models.BasicAuth{
+		AuthUser:  r.FormValue("username"),
+		AuthPass:  r.FormValue("password"),
+		LoginPass: r.FormValue("login_pass"),
+	}
+
+	urls, err := services.ParseURLList(list, limit)
+	if err == nil {
+		err = h.CrawlerService.StartListCrawler(p, basicAuth, urls, followRedirects)
+	}
+
+	if errors.Is(err, services.ErrEmptyURLList) {
+		pageView := &PageView{
+			Lang:      user.Lang,
+			Theme:     user.Theme,
+			User:      *user,
+			PageTitle: "CRAWL_LIST_PAGE_TITLE",
+			Data: struct {
+				Project models.Project
+				Error   bool
+			}{Project: p, Error: true},
+		}
+
+		h.Renderer.RenderTemplate(w, "crawl_list", pageView, user.Lang)
+		return
+	}
+
+	if err != nil {
+		log.Printf("start list crawler for %s error: %v\n", p.URL, err)
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/crawl/live?pid="+strconv.Itoa(pid), http.StatusSeeOther)
+}
+
 // liveCrawlHandler handles the request for the live crawling of a project.
 // It expects a query parameter "pid" containing the project id to be crawled.
 // This handler renders a page that will connect via websockets to display the progress
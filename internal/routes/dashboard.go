@@ -13,7 +13,8 @@ type dashboardHandler struct {
 }
 
 // indexHandler handles the dashboard of a project with all the needed data to render
-// the charts. It expects a query parameter "pid" containing the project id.
+// the charts. It expects a query parameter "pid" containing the project id, and an optional
+// "cid" parameter with the id of the crawl. If "cid" is not set the last crawl is loaded.
 func (h *dashboardHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 	user, ok := h.CookieSession.GetUser(r.Context())
 	if !ok {
@@ -27,7 +28,12 @@ func (h *dashboardHandler) indexHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
@@ -39,23 +45,27 @@ func (h *dashboardHandler) indexHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	data := struct {
-		ProjectView       *models.ProjectView
-		MediaChart        *models.Chart
-		StatusChart       *models.Chart
-		Crawls            []models.Crawl
-		CanonicalCount    *models.CanonicalCount
-		AltCount          *models.AltCount
-		SchemeCount       *models.SchemeCount
-		StatusCodeByDepth []models.StatusCodeByDepth
+		ProjectView        *models.ProjectView
+		MediaChart         *models.Chart
+		StatusChart        *models.Chart
+		Crawls             []models.Crawl
+		KeptCrawls         []models.Crawl
+		CanonicalCount     *models.CanonicalCount
+		AltCount           *models.AltCount
+		SchemeCount        *models.SchemeCount
+		StatusCodeByDepth  []models.StatusCodeByDepth
+		TimingDistribution []models.TimingDistribution
 	}{
-		ProjectView:       pv,
-		MediaChart:        h.DashboardService.GetMediaCount(pv.Crawl.Id),
-		StatusChart:       h.DashboardService.GetStatusCount(pv.Crawl.Id),
-		Crawls:            h.CrawlerService.GetLastCrawls(pv.Project),
-		CanonicalCount:    h.DashboardService.GetCanonicalCount(pv.Crawl.Id),
-		AltCount:          h.DashboardService.GetImageAltCount(pv.Crawl.Id),
-		SchemeCount:       h.DashboardService.GetSchemeCount(pv.Crawl.Id),
-		StatusCodeByDepth: h.DashboardService.GetStatusCodeByDepth(pv.Crawl.Id),
+		ProjectView:        pv,
+		MediaChart:         h.DashboardService.GetMediaCount(pv.Crawl.Id),
+		StatusChart:        h.DashboardService.GetStatusCount(pv.Crawl.Id),
+		Crawls:             h.CrawlerService.GetLastCrawls(pv.Project),
+		KeptCrawls:         h.CrawlerService.GetKeptCrawls(pv.Project),
+		CanonicalCount:     h.DashboardService.GetCanonicalCount(pv.Crawl.Id),
+		AltCount:           h.DashboardService.GetImageAltCount(pv.Crawl.Id),
+		SchemeCount:        h.DashboardService.GetSchemeCount(pv.Crawl.Id),
+		StatusCodeByDepth:  h.DashboardService.GetStatusCodeByDepth(pv.Crawl.Id),
+		TimingDistribution: h.DashboardService.GetTimingDistribution(pv.Crawl.Id),
 	}
 
 	pageView := &PageView{
@@ -0,0 +1,131 @@
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+type diffHandler struct {
+	*services.Container
+}
+
+// indexHandler handles the crawl diff page, which compares two of the project's kept crawls.
+// It expects a query parameter "pid" containing the project id and the optional "from" and "to"
+// parameters with the ids of the crawls to be compared. If they are not set the last crawl is
+// compared to the previous one. The "t" and "p" parameters are used to list the changes of a
+// specific type.
+func (h *diffHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	pv, crawls, from, to, err := h.getCrawls(r, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	t := r.URL.Query().Get("t")
+	page, err := strconv.Atoi(r.URL.Query().Get("p"))
+	if err != nil {
+		page = 1
+	}
+
+	data := &models.CrawlDiffView{
+		ProjectView: pv,
+		Crawls:      crawls,
+		Diff:        h.DiffService.GetCrawlDiff(from, to),
+		Type:        t,
+	}
+
+	if t != "" {
+		data.PaginatorView, err = h.DiffService.GetPaginatedChanges(from, to, t, page)
+		if err != nil {
+			data.Type = ""
+		}
+	}
+
+	pageView := &PageView{
+		Lang:      user.Lang,
+		Theme:     user.Theme,
+		User:      *user,
+		PageTitle: "CRAWL_DIFF_PAGE_TITLE",
+		Data:      data,
+	}
+
+	h.Renderer.RenderTemplate(w, "diff", pageView, user.Lang)
+}
+
+// exportHandler exports the changes between two crawls as a CSV file.
+// It expects the same query parameters as the indexHandler.
+func (h *diffHandler) exportHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	pv, _, from, to, err := h.getCrawls(r, user.Id)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	fileName := pv.Project.Host + " diff " + from.Start.Format("2006-01-02") + " " + to.Start.Format("2006-01-02") + " " + time.Now().Format("2006-01-02")
+	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.csv\"", fileName))
+	h.ExportService.ExportCrawlDiff(user.Lang, w, from, to)
+}
+
+// getCrawls returns the project view, the project's kept crawls and the two crawls to be compared
+// from the request's query parameters. Both crawls must be kept crawls of the user's project.
+func (h *diffHandler) getCrawls(r *http.Request, uid int) (*models.ProjectView, []models.Crawl, *models.Crawl, *models.Crawl, error) {
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	pv, err := h.ProjectViewService.GetProjectView(pid, uid)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	crawls := h.CrawlerService.GetKeptCrawls(pv.Project)
+	if len(crawls) < 2 {
+		return nil, nil, nil, nil, errors.New("not enough crawls to compare")
+	}
+
+	fromId, err := strconv.ParseInt(r.URL.Query().Get("from"), 10, 64)
+	if err != nil {
+		fromId = crawls[1].Id
+	}
+
+	toId, err := strconv.ParseInt(r.URL.Query().Get("to"), 10, 64)
+	if err != nil {
+		toId = crawls[0].Id
+	}
+
+	var from, to *models.Crawl
+	for i := range crawls {
+		if crawls[i].Id == fromId {
+			from = &crawls[i]
+		}
+
+		if crawls[i].Id == toId {
+			to = &crawls[i]
+		}
+	}
+
+	if from == nil || to == nil {
+		return nil, nil, nil, nil, errors.New("crawl not found")
+	}
+
+	return pv, crawls, from, to, nil
+}
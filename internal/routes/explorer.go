@@ -17,6 +17,7 @@ type explorerHandler struct {
 // is empty, it loads all the pagereports.
 // It expects a query parameter "pid" containing the project id, the "p" parameter containing the current
 // page in the paginator, and the "term" parameter used to perform the pagereport search.
+// The optional "cid" parameter contains the id of the crawl to be explored.
 func (h *explorerHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 	user, ok := h.CookieSession.GetUser(r.Context())
 	if !ok {
@@ -35,7 +36,12 @@ func (h *explorerHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 		page = 1
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
@@ -49,10 +55,14 @@ func (h *explorerHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	extractors := h.ReportService.GetExtractionNames(pv.Crawl.Id)
+
 	view := models.ExplorerView{
 		ProjectView:   pv,
 		Term:          term,
 		PaginatorView: paginatorView,
+		Extractors:    extractors,
+		Extractions:   h.ReportService.GetExtractedValues(pv.Crawl.Id, paginatorView.PageReports, extractors),
 	}
 
 	v := &PageView{
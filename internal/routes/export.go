@@ -63,7 +63,8 @@ func (h *exportHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 
 // csvHandler exports the pagereports of a specific project as a CSV file by issue type.
 // It expects a "pid" query parameter with the project's id. If the "eid" query parameter
-// is set, it exports the pagereports with an specific issue type.
+// is set, it exports the pagereports with an specific issue type. If the "search" query
+// parameter is set, it exports the pagereports matching the named custom search rule.
 func (h *exportHandler) csvHandler(w http.ResponseWriter, r *http.Request) {
 	user, ok := h.CookieSession.GetUser(r.Context())
 	if !ok {
@@ -84,14 +85,28 @@ func (h *exportHandler) csvHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	eid := r.URL.Query().Get("eid")
+	search := r.URL.Query().Get("search")
 	fileName := pv.Project.Host + " crawl " + time.Now().Format("2006-01-02")
 	if eid != "" {
 		fileName = fileName + "-" + eid
+	} else if search != "" {
+		fileName = fileName + "-" + search
+	}
+
+	// The values of the custom extraction rules are exported as additional columns.
+	extractors := h.ReportService.GetExtractionNames(pv.Crawl.Id)
+	var prStream <-chan *models.PageReport
+	if eid == "" && search != "" {
+		prStream = h.SearchService.GetPageReportsBySearch(pv.Crawl.Id, search)
+	} else {
+		prStream = h.ReportService.GetPageReporsByIssueType(pv.Crawl.Id, eid)
+	}
+	if len(extractors) > 0 {
+		prStream = h.ReportService.WithExtractions(pv.Crawl.Id, prStream)
 	}
 
-	prStream := h.ReportService.GetPageReporsByIssueType(pv.Crawl.Id, eid)
 	w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s.csv\"", fileName))
-	h.Container.ExportService.ExportPageReports(w, prStream)
+	h.Container.ExportService.ExportPageReports(w, prStream, extractors)
 }
 
 // sitemapHandler exports the crawled urls of a specific project as a sitemap.xml file.
@@ -154,15 +169,19 @@ func (h *exportHandler) resourcesHandler(w http.ResponseWriter, r *http.Request)
 	t := r.URL.Query().Get("t")
 
 	m := map[string]func(io.Writer, *models.Crawl){
-		"internal":  h.ExportService.ExportLinks,
-		"external":  h.ExportService.ExportExternalLinks,
-		"images":    h.ExportService.ExportImages,
-		"scripts":   h.ExportService.ExportScripts,
-		"styles":    h.ExportService.ExportStyles,
-		"iframes":   h.ExportService.ExportIframes,
-		"audios":    h.ExportService.ExportAudios,
-		"videos":    h.ExportService.ExportVideos,
-		"hreflangs": h.ExportService.ExportHreflangs,
+		"internal":   h.ExportService.ExportLinks,
+		"external":   h.ExportService.ExportExternalLinks,
+		"images":     h.ExportService.ExportImages,
+		"scripts":    h.ExportService.ExportScripts,
+		"styles":     h.ExportService.ExportStyles,
+		"iframes":    h.ExportService.ExportIframes,
+		"audios":     h.ExportService.ExportAudios,
+		"videos":     h.ExportService.ExportVideos,
+		"hreflangs":  h.ExportService.ExportHreflangs,
+		"redirects":  h.ExportService.ExportRedirectChains,
+		"cookies":    h.ExportService.ExportCookies,
+		"thirdparty": h.ExportService.ExportThirdPartyResources,
+		"structured": h.ExportService.ExportStructuredData,
 		"issues": func(w io.Writer, c *models.Crawl) {
 			h.ExportService.ExportAllIssues(user.Lang, w, c)
 		},
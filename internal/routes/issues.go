@@ -13,7 +13,8 @@ type issueHandler struct {
 }
 
 // indexHandler handles the issues view of a project.
-// It expects a query parameter "pid" containing the project id.
+// It expects a query parameter "pid" containing the project id and an optional "cid"
+// parameter with the crawl id.
 func (h *issueHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 	user, ok := h.CookieSession.GetUser(r.Context())
 	if !ok {
@@ -27,7 +28,12 @@ func (h *issueHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
@@ -56,7 +62,7 @@ func (h *issueHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 
 // viewHandler handles the view of the project's issues by an specific type.
 // It expects a query parameter "pid" containing the project id and an "eid" parameter
-// containing the issue type.
+// containing the issue type. The optional "cid" parameter contains the crawl id.
 func (h *issueHandler) viewHandler(w http.ResponseWriter, r *http.Request) {
 	user, ok := h.CookieSession.GetUser(r.Context())
 	if !ok {
@@ -81,7 +87,12 @@ func (h *issueHandler) viewHandler(w http.ResponseWriter, r *http.Request) {
 		page = 1
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
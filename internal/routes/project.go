@@ -14,6 +14,118 @@ type projectHandler struct {
 	*services.Container
 }
 
+// projectFormView is the data of the add and edit project forms. It contains the project being
+// edited, if any, the errors of the submitted form and the defaults and limits of the settings.
+type projectFormView struct {
+	Project         models.Project
+	Error           bool
+	CustomUserAgent bool
+
+	URLError               bool
+	UserAgentError         bool
+	CrawlRetentionError    bool
+	CrawlScheduleError     bool
+	CrawlScheduleAuthError bool
+	CrawlWorkersError      bool
+	CrawlDelayError        bool
+	CrawlRateError         bool
+	CrawlTimeoutError      bool
+	CrawlLimitError        bool
+	CrawlDepthError        bool
+	CrawlScopeError        bool
+	IgnoredParamsError     bool
+	CustomHeadersError     bool
+	CustomCookiesError     bool
+	ExtractorsError        bool
+	CustomSearchError      bool
+	FormLoginError         bool
+	ProxyError             bool
+	RequestTimeoutError    bool
+	RequestRetriesError    bool
+
+	UserAgent     string
+	CrawlLimit    int
+	IgnoredParams string
+
+	MaxCrawlRetention   int
+	MaxCrawlWorkers     int
+	MaxCrawlDelay       int
+	MaxCrawlTimeout     int
+	MaxCrawlLimit       int
+	MaxDepth            int
+	MaxScopeRulesLength int
+	MaxIgnoredParams    int
+	MaxCustomHeaders    int
+	MaxLoginURL         int
+	MaxLoginField       int
+	MaxProxyURL         int
+	MaxRequestTimeout   int
+	MaxRequestRetries   int
+	MaxExtractors       int
+	MaxCustomSearch     int
+}
+
+// newProjectFormView returns the projectFormView with the defaults and limits of the project
+// settings. The project is nil in the add project form. If err is not nil the form has errors
+// and the fields of the settings that are not valid are set.
+func (h *projectHandler) newProjectFormView(p *models.Project, err error) *projectFormView {
+	v := &projectFormView{
+		UserAgent:           h.Config.Crawler.Agent,
+		CrawlLimit:          h.ProjectService.DefaultCrawlLimit(),
+		IgnoredParams:       h.ProjectService.DefaultIgnoredParams(),
+		MaxCrawlRetention:   services.MaxCrawlRetention,
+		MaxCrawlWorkers:     services.MaxCrawlWorkers,
+		MaxCrawlDelay:       services.MaxCrawlDelay,
+		MaxCrawlTimeout:     services.MaxCrawlTimeout,
+		MaxCrawlLimit:       h.ProjectService.MaxCrawlLimit(),
+		MaxDepth:            h.ProjectService.MaxDepth(),
+		MaxScopeRulesLength: services.MaxScopeRulesLength,
+		MaxIgnoredParams:    services.MaxIgnoredParams,
+		MaxCustomHeaders:    services.MaxCustomHeaders,
+		MaxLoginURL:         services.MaxLoginURL,
+		MaxLoginField:       services.MaxLoginField,
+		MaxProxyURL:         services.MaxProxyURL,
+		MaxRequestTimeout:   services.MaxRequestTimeout,
+		MaxRequestRetries:   services.MaxRequestRetries,
+		MaxExtractors:       services.MaxExtractors,
+		MaxCustomSearch:     services.MaxCustomSearch,
+	}
+
+	// The proxy password is never displayed in the form.
+	if p != nil {
+		v.Project = *p
+		v.Project.Proxy = services.RedactProxy(p.Proxy)
+		v.CustomUserAgent = h.Config.Crawler.Agent != p.UserAgent
+	}
+
+	if err != nil {
+		v.Error = true
+		v.URLError = errors.Is(err, services.ErrProtocolNotSupported)
+		v.UserAgentError = errors.Is(err, services.ErrUserAgent)
+		v.CrawlRetentionError = errors.Is(err, services.ErrCrawlRetention)
+		v.CrawlScheduleError = errors.Is(err, services.ErrCrawlSchedule)
+		v.CrawlScheduleAuthError = errors.Is(err, services.ErrCrawlScheduleAuth)
+		v.CrawlWorkersError = errors.Is(err, services.ErrCrawlWorkers)
+		v.CrawlDelayError = errors.Is(err, services.ErrCrawlDelay)
+		v.CrawlRateError = errors.Is(err, services.ErrCrawlRate)
+		v.CrawlTimeoutError = errors.Is(err, services.ErrCrawlTimeout)
+		v.CrawlLimitError = errors.Is(err, services.ErrCrawlLimit)
+		v.CrawlDepthError = errors.Is(err, services.ErrCrawlDepth)
+		v.CrawlScopeError = errors.Is(err, services.ErrCrawlScope)
+		v.IgnoredParamsError = errors.Is(err, services.ErrIgnoredParams)
+		v.CustomHeadersError = errors.Is(err, services.ErrCustomHeaders)
+		v.CustomCookiesError = errors.Is(err, services.ErrCustomCookies)
+		v.ExtractorsError = errors.Is(err, services.ErrExtractors)
+		v.CustomSearchError = errors.Is(err, services.ErrCustomSearch)
+		v.FormLoginError = errors.Is(err, services.ErrFormLogin)
+		v.ProxyError = errors.Is(err, services.ErrProxy)
+		v.RequestTimeoutError = errors.Is(err, services.ErrRequestTimeout)
+		v.RequestRetriesError = errors.Is(err, services.ErrRequestRetries)
+	}
+
+	return v
+}
+
 // indexHandler Handles the user homepage request and lists all the user's projects.
 func (h *projectHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 
@@ -66,11 +178,7 @@ func (h *projectHandler) addGetHandler(w http.ResponseWriter, r *http.Request) {
 		Theme:     user.Theme,
 		User:      *user,
 		PageTitle: "ADD_PROJECT_PAGE_TITLE",
-		Data: &struct {
-			URLError       bool
-			UserAgentError bool
-			UserAgent      string
-		}{UserAgent: h.Config.Crawler.Agent},
+		Data:      h.newProjectFormView(nil, nil),
 	}
 
 	h.Renderer.RenderTemplate(w, "project_add", pageView, user.Lang)
@@ -133,6 +241,11 @@ func (h *projectHandler) addPostHandler(w http.ResponseWriter, r *http.Request)
 		archive = false
 	}
 
+	removeIndex, err := strconv.ParseBool(r.FormValue("remove_index"))
+	if err != nil {
+		removeIndex = false
+	}
+
 	customUserAgent, err := strconv.ParseBool(r.FormValue("custom_user_agent"))
 	if err != nil {
 		customUserAgent = false
@@ -143,6 +256,13 @@ func (h *projectHandler) addPostHandler(w http.ResponseWriter, r *http.Request)
 		userAgent = r.FormValue("custom_user_agent_text")
 	}
 
+	crawlRetention, err := strconv.Atoi(r.FormValue("crawl_retention"))
+	if err != nil {
+		crawlRetention = 1
+	}
+
+	crawlLimit, maxDepth := crawlLimits(r)
+
 	project := &models.Project{
 		URL:                r.FormValue("url"),
 		IgnoreRobotsTxt:    ignoreRobotsTxt,
@@ -154,24 +274,35 @@ func (h *projectHandler) addPostHandler(w http.ResponseWriter, r *http.Request)
 		CheckExternalLinks: checkExternalLinks,
 		Archive:            archive,
 		UserAgent:          userAgent,
+		CrawlRetention:     crawlRetention,
+		CrawlSchedule:      crawlSchedule(r),
+		CrawlLimit:         crawlLimit,
+		MaxDepth:           maxDepth,
+		IncludeRules:       r.FormValue("include_rules"),
+		ExcludeRules:       r.FormValue("exclude_rules"),
+		IgnoredParams:      r.FormValue("ignored_params"),
+		RemoveIndex:        removeIndex,
+		CustomHeaders:      r.FormValue("custom_headers"),
+		CustomCookies:      r.FormValue("custom_cookies"),
+		Extractors:         r.FormValue("extractors"),
+		CustomSearch:       r.FormValue("custom_search"),
+		Proxy:              r.FormValue("proxy"),
+	}
+	project.RequestTimeout, project.RequestRetries = requestPolicy(r)
+	formLogin(r, project)
+
+	err = crawlPoliteness(r, project)
+	if err == nil {
+		err = h.ProjectService.SaveProject(project, user.Id)
 	}
 
-	err = h.ProjectService.SaveProject(project, user.Id)
 	if err != nil {
 		pageView := &PageView{
 			Lang:      user.Lang,
 			Theme:     user.Theme,
 			User:      *user,
 			PageTitle: "ADD_PROJECT_PAGE_TITLE",
-			Data: &struct {
-				URLError       bool
-				UserAgentError bool
-				UserAgent      string
-			}{
-				URLError:       errors.Is(err, services.ErrProtocolNotSupported),
-				UserAgentError: errors.Is(err, services.ErrUserAgent),
-				UserAgent:      h.Config.Crawler.Agent,
-			},
+			Data:      h.newProjectFormView(nil, err),
 		}
 		h.Renderer.RenderTemplate(w, "project_add", pageView, user.Lang)
 		return
@@ -229,22 +360,12 @@ func (h *projectHandler) editGetHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	data := &struct {
-		Project         models.Project
-		Error           bool
-		UserAgentError  bool
-		CustomUserAgent bool
-	}{
-		Project:         p,
-		CustomUserAgent: h.Config.Crawler.Agent != p.UserAgent,
-	}
-
 	pageView := &PageView{
 		Lang:      user.Lang,
 		Theme:     user.Theme,
 		User:      *user,
 		PageTitle: "EDIT_PROJECT_PAGE_TITLE",
-		Data:      data,
+		Data:      h.newProjectFormView(&p, nil),
 	}
 
 	h.Renderer.RenderTemplate(w, "project_edit", pageView, user.Lang)
@@ -330,24 +451,41 @@ func (h *projectHandler) editPostHandler(w http.ResponseWriter, r *http.Request)
 		p.UserAgent = h.Config.Crawler.Agent
 	}
 
-	err = h.ProjectService.UpdateProject(&p)
+	p.CrawlRetention, err = strconv.Atoi(r.FormValue("crawl_retention"))
+	if err != nil {
+		p.CrawlRetention = 1
+	}
+
+	p.CrawlSchedule = crawlSchedule(r)
+	p.CrawlLimit, p.MaxDepth = crawlLimits(r)
+	p.IncludeRules = r.FormValue("include_rules")
+	p.ExcludeRules = r.FormValue("exclude_rules")
+	p.IgnoredParams = r.FormValue("ignored_params")
+	p.CustomHeaders = r.FormValue("custom_headers")
+	p.CustomCookies = r.FormValue("custom_cookies")
+	p.Extractors = r.FormValue("extractors")
+	p.CustomSearch = r.FormValue("custom_search")
+	p.Proxy = services.MergeProxy(r.FormValue("proxy"), p.Proxy)
+	p.RequestTimeout, p.RequestRetries = requestPolicy(r)
+	formLogin(r, &p)
+
+	p.RemoveIndex, err = strconv.ParseBool(r.FormValue("remove_index"))
+	if err != nil {
+		p.RemoveIndex = false
+	}
+
+	err = crawlPoliteness(r, &p)
+	if err == nil {
+		err = h.ProjectService.UpdateProject(&p)
+	}
+
 	if err != nil {
 		pageView := &PageView{
 			Lang:      user.Lang,
 			Theme:     user.Theme,
 			User:      *user,
 			PageTitle: "EDIT_PROJECT_PAGE_TITLE",
-			Data: &struct {
-				Project         models.Project
-				Error           bool
-				UserAgentError  bool
-				CustomUserAgent bool
-			}{
-				Project:         p,
-				Error:           true,
-				UserAgentError:  errors.Is(err, services.ErrUserAgent),
-				CustomUserAgent: h.Config.Crawler.Agent != p.UserAgent,
-			},
+			Data:      h.newProjectFormView(&p, err),
 		}
 
 		h.Renderer.RenderTemplate(w, "project_edit", pageView, user.Lang)
@@ -356,3 +494,92 @@ func (h *projectHandler) editPostHandler(w http.ResponseWriter, r *http.Request)
 
 	http.Redirect(w, r, "/", http.StatusSeeOther)
 }
+
+// crawlSchedule returns the crawl schedule selected in the project form. The "custom" option
+// uses the cron expression entered by the user.
+func crawlSchedule(r *http.Request) string {
+	schedule := r.FormValue("crawl_schedule")
+	if schedule == "custom" {
+		return r.FormValue("crawl_schedule_cron")
+	}
+
+	return schedule
+}
+
+// crawlPoliteness sets the number of workers, the min and max delay between requests, the
+// max requests per second and the timeout from the project form. Empty values are set to the
+// defaults, while values that are not valid numbers return the setting's form error.
+func crawlPoliteness(r *http.Request, p *models.Project) error {
+	atoi := func(name string, d int, e error) (int, error) {
+		v := r.FormValue(name)
+		if v == "" {
+			return d, nil
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, e
+		}
+
+		return n, nil
+	}
+
+	var err error
+	if p.CrawlWorkers, err = atoi("crawl_workers", services.DefaultCrawlWorkers, services.ErrCrawlWorkers); err != nil {
+		return err
+	}
+
+	if p.CrawlMinDelay, err = atoi("crawl_min_delay", services.DefaultCrawlMinDelay, services.ErrCrawlDelay); err != nil {
+		return err
+	}
+
+	if p.CrawlMaxDelay, err = atoi("crawl_max_delay", services.DefaultCrawlMaxDelay, services.ErrCrawlDelay); err != nil {
+		return err
+	}
+
+	if p.CrawlTimeout, err = atoi("crawl_timeout", services.DefaultCrawlTimeout, services.ErrCrawlTimeout); err != nil {
+		return err
+	}
+
+	p.CrawlMaxRPS = 0
+	if v := r.FormValue("crawl_max_rps"); v != "" {
+		if p.CrawlMaxRPS, err = strconv.ParseFloat(v, 64); err != nil {
+			return services.ErrCrawlRate
+		}
+	}
+
+	return nil
+}
+
+// crawlLimits returns the max number of URLs and the max depth from the project form.
+// Values that are not valid numbers are returned as zero, so the project's defaults are used.
+func crawlLimits(r *http.Request) (crawlLimit, maxDepth int) {
+	crawlLimit, _ = strconv.Atoi(r.FormValue("crawl_limit"))
+	maxDepth, _ = strconv.Atoi(r.FormValue("max_depth"))
+
+	return
+}
+
+// requestPolicy returns the request timeout and the number of retries from the project form.
+// The default number of retries is used if the value is not a valid number.
+func requestPolicy(r *http.Request) (timeout, retries int) {
+	timeout, _ = strconv.Atoi(r.FormValue("request_timeout"))
+
+	retries, err := strconv.Atoi(r.FormValue("request_retries"))
+	if err != nil {
+		retries = services.DefaultRequestRetries
+	}
+
+	return
+}
+
+// formLogin sets the project's form login settings from the form values. The password is not
+// part of the project, it is entered every time the project is crawled.
+func formLogin(r *http.Request, p *models.Project) {
+	p.LoginURL = r.FormValue("login_url")
+	p.LoginUserField = r.FormValue("login_user_field")
+	p.LoginPassField = r.FormValue("login_pass_field")
+	p.LoginUser = r.FormValue("login_user")
+	p.LoginSuccess = r.FormValue("login_success")
+	p.LoginLoggedOut = r.FormValue("login_logged_out")
+}
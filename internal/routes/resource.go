@@ -16,6 +16,7 @@ type resourceHandler struct {
 // indexHandler handles the HTTP request for the resources view page.
 // It expects the following query parameters:
 // - "pid" containing the project id.
+// - "cid" the id of the crawl, which defaults to the project's last crawl.
 // - "rid" the id of the resource to be loaded.
 // - "eid" the id of the issue type from wich the user loaded this resource.
 // - "ep" the explorer page number from which the user loaded this resource.
@@ -57,7 +58,12 @@ func (h *resourceHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
 		page = 1
 	}
 
-	pv, err := h.ProjectViewService.GetProjectView(pid, user.Id)
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
 	if err != nil {
 		http.Redirect(w, r, "/", http.StatusSeeOther)
 		return
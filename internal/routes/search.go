@@ -0,0 +1,123 @@
+package routes
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+type searchHandler struct {
+	*services.Container
+}
+
+// indexHandler handles the custom search view of a project, listing the number of pages
+// matching each of the project's custom search rules.
+// It expects a query parameter "pid" containing the project id and an optional "cid"
+// parameter with the crawl id.
+func (h *searchHandler) indexHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	if pv.Crawl.TotalURLs == 0 {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	data := models.CustomSearchView{
+		ProjectView: pv,
+		Counts:      h.SearchService.GetCustomSearchCounts(&pv.Project, pv.Crawl.Id),
+	}
+
+	v := &PageView{
+		Lang:      user.Lang,
+		Theme:     user.Theme,
+		Data:      data,
+		User:      *user,
+		PageTitle: "CUSTOM_SEARCH_PAGE_TITLE",
+	}
+
+	h.Renderer.RenderTemplate(w, "custom_search", v, user.Lang)
+}
+
+// viewHandler handles the view of the pages matching a custom search rule.
+// It expects a query parameter "pid" containing the project id and a "name" parameter
+// containing the rule name. The optional "cid" parameter contains the crawl id.
+func (h *searchHandler) viewHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.CookieSession.GetUser(r.Context())
+	if !ok {
+		http.Redirect(w, r, "/signout", http.StatusSeeOther)
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	pid, err := strconv.Atoi(r.URL.Query().Get("pid"))
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	page, err := strconv.Atoi(r.URL.Query().Get("p"))
+	if err != nil {
+		page = 1
+	}
+
+	cid, err := strconv.ParseInt(r.URL.Query().Get("cid"), 10, 64)
+	if err != nil {
+		cid = 0
+	}
+
+	pv, err := h.ProjectViewService.GetProjectCrawlView(pid, user.Id, cid)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	paginatorView, err := h.SearchService.GetPaginatedReportsBySearch(pv.Crawl.Id, page, name)
+	if err != nil {
+		http.Redirect(w, r, "/", http.StatusSeeOther)
+		return
+	}
+
+	data := models.CustomSearchPagesView{
+		ProjectView:   pv,
+		Name:          name,
+		PaginatorView: paginatorView,
+	}
+
+	v := &PageView{
+		Lang:      user.Lang,
+		Theme:     user.Theme,
+		Data:      data,
+		User:      *user,
+		PageTitle: "CUSTOM_SEARCH_DETAIL_PAGE_TITLE",
+	}
+
+	h.Renderer.RenderTemplate(w, "custom_search_view", v, user.Lang)
+}
@@ -0,0 +1,198 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a parsed cron expression. Each field is a bitset with the allowed values.
+type Schedule struct {
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// Set if the day of month or day of week fields are restricted.
+	// If both are restricted a day matches if any of them match, as in cron.
+	domRestricted bool
+	dowRestricted bool
+}
+
+// Descriptors that can be used instead of a cron expression.
+var descriptors = map[string]string{
+	"@daily":  "0 0 * * *",
+	"@weekly": "0 0 * * 0",
+}
+
+type bounds struct {
+	min int
+	max int
+}
+
+var (
+	minuteBounds = bounds{0, 59}
+	hourBounds   = bounds{0, 23}
+	domBounds    = bounds{1, 31}
+	monthBounds  = bounds{1, 12}
+	dowBounds    = bounds{0, 7}
+)
+
+// Max number of years to search for the next activation time.
+const maxYears = 5
+
+var ErrInvalidSchedule = errors.New("invalid schedule")
+
+// Parse parses a standard five field cron expression with minute, hour, day of month,
+// month and day of week, or one of the @daily and @weekly descriptors.
+// The fields support wildcards, ranges, steps and comma separated lists.
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if d, ok := descriptors[spec]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, found %d", ErrInvalidSchedule, len(fields))
+	}
+
+	s := &Schedule{}
+	var err error
+
+	if s.minute, err = parseField(fields[0], minuteBounds); err != nil {
+		return nil, err
+	}
+
+	if s.hour, err = parseField(fields[1], hourBounds); err != nil {
+		return nil, err
+	}
+
+	if s.dom, err = parseField(fields[2], domBounds); err != nil {
+		return nil, err
+	}
+
+	if s.month, err = parseField(fields[3], monthBounds); err != nil {
+		return nil, err
+	}
+
+	if s.dow, err = parseField(fields[4], dowBounds); err != nil {
+		return nil, err
+	}
+
+	// Sunday can be specified as 0 or 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+
+	s.domRestricted = fields[2] != "*"
+	s.dowRestricted = fields[4] != "*"
+
+	return s, nil
+}
+
+// Next returns the first activation time after t. It returns a zero time if there's no
+// activation time in the next few years, for instance with schedules set for February 30th.
+func (s *Schedule) Next(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !s.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// matchDay returns true if the day of month or the day of week of t match the schedule.
+func (s *Schedule) matchDay(t time.Time) bool {
+	dom := s.dom&(1<<uint(t.Day())) != 0
+	dow := s.dow&(1<<uint(t.Weekday())) != 0
+
+	if s.domRestricted && s.dowRestricted {
+		return dom || dow
+	}
+
+	return dom && dow
+}
+
+// parseField parses a comma separated list of ranges into a bitset.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		r, err := parseRange(part, b)
+		if err != nil {
+			return 0, err
+		}
+
+		bits |= r
+	}
+
+	return bits, nil
+}
+
+// parseRange parses a wildcard, a single value or a range with an optional step.
+func parseRange(r string, b bounds) (uint64, error) {
+	start, end, step := b.min, b.max, 1
+
+	rangePart, stepPart, hasStep := strings.Cut(r, "/")
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("%w: step %q", ErrInvalidSchedule, r)
+		}
+		step = n
+	}
+
+	if rangePart != "*" {
+		low, high, isRange := strings.Cut(rangePart, "-")
+
+		var err error
+		if start, err = strconv.Atoi(low); err != nil {
+			return 0, fmt.Errorf("%w: value %q", ErrInvalidSchedule, r)
+		}
+
+		end = start
+		if isRange {
+			if end, err = strconv.Atoi(high); err != nil {
+				return 0, fmt.Errorf("%w: value %q", ErrInvalidSchedule, r)
+			}
+		} else if hasStep {
+			end = b.max
+		}
+	}
+
+	if start < b.min || end > b.max || start > end {
+		return 0, fmt.Errorf("%w: out of range %q", ErrInvalidSchedule, r)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << uint(i)
+	}
+
+	return bits, nil
+}
@@ -0,0 +1,74 @@
+package schedule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/schedule"
+)
+
+// Test Parse with valid and invalid cron expressions.
+func TestParse(t *testing.T) {
+	table := []struct {
+		spec  string
+		valid bool
+	}{
+		{"@daily", true},
+		{"@weekly", true},
+		{"0 9 * * 1", true},
+		{"*/15 * * * *", true},
+		{"0 8-18/2 1,15 * 1-5", true},
+		{"0 0 * * 7", true},
+		{"", false},
+		{"@yearly", false},
+		{"* * * *", false},
+		{"60 * * * *", false},
+		{"0 24 * * *", false},
+		{"0 0 0 * *", false},
+		{"0 0 * 13 *", false},
+		{"*/0 * * * *", false},
+		{"5-1 * * * *", false},
+		{"a * * * *", false},
+	}
+
+	for _, tc := range table {
+		_, err := schedule.Parse(tc.spec)
+		if (err == nil) != tc.valid {
+			t.Errorf("%q: valid %v, got error %v", tc.spec, tc.valid, err)
+		}
+	}
+}
+
+// Test the next activation time of different schedules.
+func TestNext(t *testing.T) {
+	// Wednesday.
+	now := time.Date(2024, time.January, 10, 10, 30, 15, 0, time.UTC)
+
+	table := []struct {
+		spec string
+		next time.Time
+	}{
+		{"@daily", time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1", time.Date(2024, time.January, 15, 9, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 10, 10, 45, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, time.January, 11, 10, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 5", time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tc := range table {
+		s, err := schedule.Parse(tc.spec)
+		if err != nil {
+			t.Errorf("%q: %v", tc.spec, err)
+			continue
+		}
+
+		next := s.Next(now)
+		if !next.Equal(tc.next) {
+			t.Errorf("%q: next %v, expected %v", tc.spec, next, tc.next)
+		}
+	}
+}
@@ -32,15 +32,25 @@ type Container struct {
 	CookieSession      *CookieSession
 	ArchiveService     *ArchiveService
 	ReplayService      *ReplayService
-
-	db                   *sql.DB
-	issueRepository      *repository.IssueRepository
-	pageReportRepository *repository.PageReportRepository
-	userRepository       *repository.UserRepository
-	projectRepository    *repository.ProjectRepository
-	exportRepository     *repository.ExportRepository
-	crawlRepository      *repository.CrawlRepository
-	dashboardRepository  *repository.DashboardRepository
+	DiffService        *DiffService
+	SchedulerService   *SchedulerService
+	RedirectService    *RedirectService
+	SearchService      *CustomSearchService
+	DuplicateService   *NearDuplicateService
+
+	db                    *sql.DB
+	issueRepository       *repository.IssueRepository
+	pageReportRepository  *repository.PageReportRepository
+	userRepository        *repository.UserRepository
+	projectRepository     *repository.ProjectRepository
+	exportRepository      *repository.ExportRepository
+	crawlRepository       *repository.CrawlRepository
+	dashboardRepository   *repository.DashboardRepository
+	diffRepository        *repository.DiffRepository
+	redirectRepository    *repository.RedirectRepository
+	certificateRepository *repository.CertificateRepository
+	searchRepository      *repository.CustomSearchRepository
+	duplicateRepository   *repository.NearDuplicateRepository
 }
 
 func NewContainer(configFile string) *Container {
@@ -59,7 +69,12 @@ func NewContainer(configFile string) *Container {
 	c.InitProjectService()
 	c.InitProjectViewService()
 	c.InitExportService()
+	c.InitDiffService()
+	c.InitRedirectService()
+	c.InitSearchService()
+	c.InitDuplicateService()
 	c.InitCrawlerService()
+	c.InitSchedulerService()
 	c.InitRenderer()
 	c.InitCookieSession()
 	c.InitReplayService()
@@ -108,9 +123,11 @@ func (c *Container) InitRepositories() {
 	c.exportRepository = &repository.ExportRepository{DB: c.db}
 	c.crawlRepository = &repository.CrawlRepository{DB: c.db}
 	c.dashboardRepository = &repository.DashboardRepository{DB: c.db}
-
-	// Clean up unfinished crawls.
-	c.crawlRepository.DeleteUnfinishedCrawls()
+	c.diffRepository = &repository.DiffRepository{DB: c.db}
+	c.redirectRepository = &repository.RedirectRepository{DB: c.db}
+	c.certificateRepository = &repository.CertificateRepository{DB: c.db}
+	c.searchRepository = &repository.CustomSearchRepository{DB: c.db}
+	c.duplicateRepository = &repository.NearDuplicateRepository{DB: c.db}
 }
 
 // Create the PubSub broker.
@@ -128,9 +145,15 @@ func (c *Container) InitReportService() {
 	repository := &struct {
 		*repository.PageReportRepository
 		*repository.IssueRepository
+		*repository.RedirectRepository
+		*repository.CertificateRepository
+		*repository.NearDuplicateRepository
 	}{
 		c.pageReportRepository,
 		c.issueRepository,
+		c.redirectRepository,
+		c.certificateRepository,
+		c.duplicateRepository,
 	}
 
 	c.ReportService = NewReportService(repository)
@@ -143,6 +166,9 @@ func (c *Container) InitReportManager() {
 		c.ReportManager.AddPageReporter(r)
 	}
 
+	// The certificate expiry reporter uses the number of days set in the crawler's config.
+	c.ReportManager.AddPageReporter(page.NewCertificateExpiryReporter(c.Config.Crawler.CertExpiryDays))
+
 	// Create the sql multipage reporters and add them all to the reporterManager.
 	sqlReporters := multipage.NewSqlReporter(c.db)
 	for _, r := range sqlReporters.GetAllReporters() {
@@ -165,7 +191,7 @@ func (c *Container) InitProjectService() {
 		c.crawlRepository,
 	}
 
-	c.ProjectService = NewProjectService(repository, c.ArchiveService)
+	c.ProjectService = NewProjectService(repository, c.ArchiveService, c.Config.Crawler)
 
 	// UserService DeleteHooks are called when a user is deleted.
 	// Add a DeleteHook so it deletes all user projects and crawl
@@ -188,27 +214,84 @@ func (c *Container) InitProjectViewService() {
 
 // Create the Export service.
 func (c *Container) InitExportService() {
-	c.ExportService = NewExporter(c.exportRepository, c.Translator)
+	repository := &struct {
+		*repository.ExportRepository
+		*repository.DiffRepository
+	}{
+		c.exportRepository,
+		c.diffRepository,
+	}
+
+	c.ExportService = NewExporter(repository, c.Translator)
+}
+
+// Create the crawl diff service.
+func (c *Container) InitDiffService() {
+	c.DiffService = NewDiffService(c.diffRepository)
+}
+
+// Create the redirect service.
+func (c *Container) InitRedirectService() {
+	c.RedirectService = NewRedirectService(c.redirectRepository)
+}
+
+// Create the custom search service.
+func (c *Container) InitSearchService() {
+	repository := &struct {
+		*repository.CustomSearchRepository
+		*repository.PageReportRepository
+	}{
+		c.searchRepository,
+		c.pageReportRepository,
+	}
+
+	c.SearchService = NewCustomSearchService(repository)
+}
+
+// Create the near-duplicate content service with the similarity set in the crawler's config.
+func (c *Container) InitDuplicateService() {
+	c.DuplicateService = NewNearDuplicateService(c.duplicateRepository, c.Config.Crawler.NearDuplicateSimilarity)
 }
 
 // Create Crawler service.
 func (c *Container) InitCrawlerService() {
+	handlerRepository := &struct {
+		*repository.PageReportRepository
+		*repository.CertificateRepository
+	}{
+		c.pageReportRepository,
+		c.certificateRepository,
+	}
+
 	crawlerServices := CrawlerServicesContainer{
-		Broker:         c.PubSubBroker,
-		ReportManager:  c.ReportManager,
-		CrawlerHandler: NewCrawlerHandler(c.pageReportRepository, c.PubSubBroker, c.ReportManager),
-		ArchiveService: c.ArchiveService,
-		Config:         c.Config.Crawler,
+		Broker:           c.PubSubBroker,
+		ReportManager:    c.ReportManager,
+		CrawlerHandler:   NewCrawlerHandler(handlerRepository, c.PubSubBroker, c.ReportManager),
+		ArchiveService:   c.ArchiveService,
+		RedirectService:  c.RedirectService,
+		DuplicateService: c.DuplicateService,
+		Config:           c.Config.Crawler,
 	}
 	repository := &struct {
 		*repository.CrawlRepository
 		*repository.IssueRepository
+		*repository.ProjectRepository
 	}{
 		c.crawlRepository,
 		c.issueRepository,
+		c.projectRepository,
 	}
 
 	c.CrawlerService = NewCrawlerService(repository, crawlerServices)
+
+	// Resume the crawls that were interrupted, the ones that can't be resumed are deleted.
+	c.CrawlerService.ResumeCrawls()
+}
+
+// Create the scheduler service and start checking for scheduled crawls.
+func (c *Container) InitSchedulerService() {
+	c.SchedulerService = NewSchedulerService(c.projectRepository, c.CrawlerService)
+	c.SchedulerService.Start()
 }
 
 // Create the dashboCallbackBuilderard service.
@@ -1,10 +1,13 @@
 package services
 
 import (
+	"crypto/tls"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"strings"
 	"sync"
@@ -13,30 +16,67 @@ import (
 	"github.com/stjudewashere/seonaut/internal/config"
 	"github.com/stjudewashere/seonaut/internal/crawler"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/urlutils"
 )
 
 const (
-	CrawlLimit      = 20000 // Max number of page reports that will be created
+	CrawlLimit      = 20000 // Default max number of page reports that will be created
 	LastCrawlsLimit = 5     // Max number returned by GetLastCrawls
-	ClientTimeout   = 10    // HTTP client timeout in seconds.
+	ClientTimeout   = 10    // Default HTTP client timeout in seconds.
+
+	CheckpointInterval = 60 // Seconds between crawl checkpoints.
 )
 
+var ErrNoCheckpoint = errors.New("crawl has no checkpoint")
+var ErrResumeBasicAuth = errors.New("crawls using basic auth can't be resumed")
+var ErrResumeFormLogin = errors.New("crawls using form login can't be resumed")
+var ErrAlreadyCrawling = errors.New("project is already being crawled")
+var ErrEmptyURLList = errors.New("the list doesn't contain any valid URL")
+
 type CrawlerServiceRepository interface {
-	SaveCrawl(models.Project) (*models.Crawl, error)
+	SaveCrawl(models.Project, models.CrawlMode) (*models.Crawl, error)
 	GetLastCrawl(p *models.Project) models.Crawl
 	GetLastCrawls(models.Project, int) []models.Crawl
-	DeleteCrawlData(c *models.Crawl)
+	GetKeptCrawls(p *models.Project) []models.Crawl
+	PruneCrawls(p *models.Project, keep int)
+	DeleteCrawl(c *models.Crawl)
+	FindUnfinishedCrawls() []models.Crawl
+
+	SaveCrawlCheckpoint(*models.Crawl, []byte) error
+	FindCrawlCheckpoint(*models.Crawl) ([]byte, error)
+	RevertToCrawlCheckpoint(*models.Crawl) error
+	DeleteCrawlCheckpoint(*models.Crawl)
+
+	FindProject(id int64) (models.Project, error)
 
 	CountIssuesByPriority(int64, int) int
 	UpdateCrawl(*models.Crawl)
 }
 
+// checkpointRequest is the serializable version of a crawler's pending request.
+type checkpointRequest struct {
+	URL            string
+	IgnoreDomain   bool
+	IgnoreMaxDepth bool
+	Method         crawler.Method
+	Depth          int
+}
+
+// checkpointData is the serializable version of the crawler's checkpoint.
+type checkpointData struct {
+	Pending []checkpointRequest
+	Seen    []string
+	Crawled int
+}
+
 type CrawlerServicesContainer struct {
-	Broker         *Broker
-	ReportManager  *ReportManager
-	CrawlerHandler *CrawlerHandler
-	ArchiveService *ArchiveService
-	Config         *config.CrawlerConfig
+	Broker           *Broker
+	ReportManager    *ReportManager
+	CrawlerHandler   *CrawlerHandler
+	ArchiveService   *ArchiveService
+	RedirectService  *RedirectService
+	DuplicateService *NearDuplicateService
+	Config           *config.CrawlerConfig
 }
 
 type CrawlerService struct {
@@ -46,6 +86,8 @@ type CrawlerService struct {
 	reportManager  *ReportManager
 	crawlerHandler *CrawlerHandler
 	ArchiveService *ArchiveService
+	redirects      *RedirectService
+	duplicates     *NearDuplicateService
 	crawlers       map[int64]*crawler.Crawler
 	lock           *sync.RWMutex
 }
@@ -58,6 +100,8 @@ func NewCrawlerService(r CrawlerServiceRepository, s CrawlerServicesContainer) *
 		reportManager:  s.ReportManager,
 		crawlerHandler: s.CrawlerHandler,
 		ArchiveService: s.ArchiveService,
+		redirects:      s.RedirectService,
+		duplicates:     s.DuplicateService,
 		crawlers:       make(map[int64]*crawler.Crawler),
 		lock:           &sync.RWMutex{},
 	}
@@ -66,75 +110,250 @@ func NewCrawlerService(r CrawlerServiceRepository, s CrawlerServicesContainer) *
 // StartCrawler creates a new crawler and crawls the project's URL.
 // It adds a new crawler for the project, it returns an error if there's one already
 // running or if there's an error creating it.
-// Finally the previous crawl's data is removed and the crawl is returned.
+// Once the crawl is finished the older crawls are pruned according to the project's
+// crawl retention setting.
 func (s *CrawlerService) StartCrawler(p models.Project, b models.BasicAuth) error {
-	previousCrawl := s.repository.GetLastCrawl(&p)
-	crawl, err := s.repository.SaveCrawl(p)
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return err
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	// The crawler is added before the crawl is saved so no crawl is created
+	// if the project is already being crawled.
+	c, err := s.addCrawler(u, &p, &b)
+	if err != nil {
+		return err
+	}
+
+	crawl, err := s.repository.SaveCrawl(p, models.CrawlModeSpider)
 	if err != nil {
+		s.removeCrawler(&p)
 		return err
 	}
 
+	go func() {
+		log.Printf("Crawling %s...", p.URL)
+		c.AddRequest(&crawler.RequestMessage{URL: u})
+
+		s.crawl(c, crawl, &p)
+	}()
+
+	return nil
+}
+
+// StartListCrawler creates a new crawler that only crawls the URLs in the list, without
+// following any links. If followRedirects is true the redirect targets are crawled as well.
+// The URLs in the list are not restricted to the project's domain.
+func (s *CrawlerService) StartListCrawler(p models.Project, b models.BasicAuth, urls []*url.URL, followRedirects bool) error {
+	if len(urls) == 0 {
+		return ErrEmptyURLList
+	}
+
 	u, err := url.Parse(p.URL)
 	if err != nil {
 		return err
 	}
 
-	if u.Path == "" {
-		u.Path = "/"
+	mode := models.CrawlModeList
+	if followRedirects {
+		mode = models.CrawlModeListRedirects
 	}
 
-	c, err := s.addCrawler(u, &p, &b)
+	c, err := s.addCrawler(u, listModeProject(p), &b)
+	if err != nil {
+		return err
+	}
+
+	crawl, err := s.repository.SaveCrawl(p, mode)
 	if err != nil {
+		s.removeCrawler(&p)
 		return err
 	}
 
 	go func() {
-		defer s.removeCrawler(&p)
-		defer s.repository.DeleteCrawlData(&previousCrawl)
-
-		callback := s.crawlerHandler.responseCallback(crawl, &p, c)
-
-		if p.Archive {
-			archiver, err := s.ArchiveService.GetArchiveWriter(&p)
-			if err != nil {
-				log.Printf("Failed to create archive: %v", err)
-			} else {
-				defer archiver.Close()
-				callback = s.crawlerHandler.archiveWrapper(callback, archiver)
-			}
+		log.Printf("Crawling a list of %d URLs in %s...", len(urls), p.URL)
+		for _, lu := range urls {
+			c.AddRequest(&crawler.RequestMessage{URL: lu, IgnoreDomain: true})
 		}
 
-		c.OnResponse(callback)
+		s.crawl(c, crawl, &p)
+	}()
 
-		log.Printf("Crawling %s...", p.URL)
-		c.AddRequest(&crawler.RequestMessage{URL: u, Data: crawlerData{}})
+	return nil
+}
 
-		// Calling Start() initiates the website crawling process and
-		// blocks execution until the crawling is complete.
-		c.Start()
+// ResumeCrawls looks for crawls that were interrupted, for instance by a server restart, and
+// resumes them from their last checkpoint. Crawls that can't be resumed are deleted.
+func (s *CrawlerService) ResumeCrawls() {
+	for _, crawl := range s.repository.FindUnfinishedCrawls() {
+		err := s.resumeCrawler(&crawl)
+		if err != nil {
+			log.Printf("Deleting unfinished crawl %d: %v", crawl.Id, err)
+			s.repository.DeleteCrawl(&crawl)
+		}
+	}
+}
 
-		crawl.RobotstxtExists = c.RobotstxtExists()
-		crawl.SitemapExists = c.SitemapExists()
-		crawl.SitemapIsBlocked = c.SitemapIsBlocked()
-		crawl.End = time.Now()
+// resumeCrawler restores the crawl's checkpoint into a new crawler and continues crawling.
+// The crawl's pagereports saved after the checkpoint are deleted, as the URLs they belong
+// to are still pending in the checkpoint and will be crawled again.
+func (s *CrawlerService) resumeCrawler(crawl *models.Crawl) error {
+	p, err := s.repository.FindProject(crawl.ProjectId)
+	if err != nil {
+		return err
+	}
 
-		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &models.Message{Name: "IssuesInit"})
-		s.reportManager.CreateMultipageIssues(crawl)
+	// The basic auth credentials are never stored, so there's no way to resume the crawl.
+	if p.BasicAuth {
+		return ErrResumeBasicAuth
+	}
 
-		crawl.IssuesEnd = time.Now()
-		crawl.CriticalIssues = s.repository.CountIssuesByPriority(crawl.Id, Critical)
-		crawl.AlertIssues = s.repository.CountIssuesByPriority(crawl.Id, Alert)
-		crawl.WarningIssues = s.repository.CountIssuesByPriority(crawl.Id, Warning)
-		crawl.TotalIssues = crawl.CriticalIssues + crawl.AlertIssues + crawl.WarningIssues
+	// Neither is the form login password.
+	if p.LoginURL != "" {
+		return ErrResumeFormLogin
+	}
 
-		s.repository.UpdateCrawl(crawl)
-		s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &models.Message{Name: "CrawlEnd", Data: crawl.TotalURLs})
-		log.Printf("Crawled %d urls in %s", crawl.TotalURLs, p.URL)
+	data, err := s.repository.FindCrawlCheckpoint(crawl)
+	if err != nil {
+		return ErrNoCheckpoint
+	}
+
+	checkpoint, err := decodeCheckpoint(data)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repository.RevertToCrawlCheckpoint(crawl); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return err
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	cp := &p
+	if crawl.Mode != models.CrawlModeSpider {
+		cp = listModeProject(p)
+	}
+
+	c, err := s.addCrawler(u, cp, &models.BasicAuth{})
+	if err != nil {
+		return err
+	}
+
+	c.Restore(checkpoint)
+
+	go func() {
+		log.Printf("Resuming crawl of %s...", p.URL)
+		s.crawl(c, crawl, &p)
 	}()
 
 	return nil
 }
 
+// crawl runs the crawler and blocks until it is done. Once the crawl is finished it creates
+// the multipage issues and updates the crawl data. Finally, the data of the crawls that exceed
+// the project's crawl retention is removed.
+func (s *CrawlerService) crawl(c *crawler.Crawler, crawl *models.Crawl, p *models.Project) {
+	defer s.removeCrawler(p)
+	defer s.repository.PruneCrawls(p, max(p.CrawlRetention, 1))
+
+	callback := s.crawlerHandler.responseCallback(crawl, p, c)
+
+	// A resumed crawl starts a new archive file, so it will only contain the URLs
+	// crawled after the crawl was resumed.
+	if p.Archive {
+		archiver, err := s.ArchiveService.GetArchiveWriter(p)
+		if err != nil {
+			log.Printf("Failed to create archive: %v", err)
+		} else {
+			defer archiver.Close()
+			callback = s.crawlerHandler.archiveWrapper(callback, archiver)
+		}
+	}
+
+	// A resumed crawl keeps the throttled responses counted before it was interrupted.
+	throttled := crawl.Throttled
+	c.OnResponse(s.checkpointWrapper(callback, crawl, c))
+
+	// If the login fails the site is crawled anyway, as a logged out visitor would see it,
+	// and the failure is recorded in the crawl.
+	if client, ok := c.Client.(*crawler.BasicClient); ok && client.Options.Login != nil {
+		if err := client.Login(c.Context()); err != nil {
+			log.Printf("Form login in %s: %v", p.URL, err)
+			crawl.LoginFailed = true
+		}
+	}
+
+	// Calling Start() initiates the website crawling process and
+	// blocks execution until the crawling is complete.
+	c.Start()
+
+	// The checkpoint is removed before creating the multipage issues. If the crawl is
+	// interrupted during this phase it can't be resumed and it will be deleted.
+	s.repository.DeleteCrawlCheckpoint(crawl)
+
+	crawl.RobotstxtExists = c.RobotstxtExists()
+	crawl.SitemapExists = c.SitemapExists()
+	crawl.SitemapIsBlocked = c.SitemapIsBlocked()
+	crawl.Throttled = throttled + c.Throttled()
+	crawl.End = time.Now()
+
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &models.Message{Name: "IssuesInit"})
+
+	// The redirect chains and near-duplicates are needed by some of the multipage issue reporters.
+	s.redirects.SaveRedirectChains(crawl)
+	s.duplicates.SaveNearDuplicates(crawl)
+	s.reportManager.CreateMultipageIssues(crawl)
+
+	crawl.IssuesEnd = time.Now()
+	crawl.CriticalIssues = s.repository.CountIssuesByPriority(crawl.Id, Critical)
+	crawl.AlertIssues = s.repository.CountIssuesByPriority(crawl.Id, Alert)
+	crawl.WarningIssues = s.repository.CountIssuesByPriority(crawl.Id, Warning)
+	crawl.TotalIssues = crawl.CriticalIssues + crawl.AlertIssues + crawl.WarningIssues
+
+	s.repository.UpdateCrawl(crawl)
+	s.broker.Publish(fmt.Sprintf("crawl-%d", p.Id), &models.Message{Name: "CrawlEnd", Data: crawl.TotalURLs})
+	log.Printf("Crawled %d urls in %s", crawl.TotalURLs, p.URL)
+}
+
+// checkpointWrapper wraps the response callback so the crawler's state is stored every
+// CheckpointInterval seconds. The checkpoint is saved after the callback returns, so all
+// the data of the processed response is already in the database.
+func (s *CrawlerService) checkpointWrapper(callback crawler.ResponseCallback, crawl *models.Crawl, c *crawler.Crawler) crawler.ResponseCallback {
+	last := time.Now()
+	throttled := crawl.Throttled
+
+	return func(r *crawler.ResponseMessage) {
+		callback(r)
+
+		if time.Since(last) < CheckpointInterval*time.Second {
+			return
+		}
+
+		last = time.Now()
+		crawl.Throttled = throttled + c.Throttled()
+		data, err := encodeCheckpoint(c.Checkpoint())
+		if err != nil {
+			log.Printf("encodeCheckpoint: crawl %d %v", crawl.Id, err)
+			return
+		}
+
+		if err := s.repository.SaveCrawlCheckpoint(crawl, data); err != nil {
+			log.Printf("SaveCrawlCheckpoint: crawl %d %v", crawl.Id, err)
+		}
+	}
+}
+
 // Get a slice with 'LastCrawlsLimit' number of the crawls
 func (s *CrawlerService) GetLastCrawls(p models.Project) []models.Crawl {
 	crawls := s.repository.GetLastCrawls(p, LastCrawlsLimit)
@@ -146,6 +365,11 @@ func (s *CrawlerService) GetLastCrawls(p models.Project) []models.Crawl {
 	return crawls
 }
 
+// GetKeptCrawls returns a slice with the project's crawls that can still be browsed.
+func (s *CrawlerService) GetKeptCrawls(p models.Project) []models.Crawl {
+	return s.repository.GetKeptCrawls(&p)
+}
+
 // StopCrawler stops a crawler. If the crawler does not exsit it will just return.
 func (s *CrawlerService) StopCrawler(p models.Project) {
 	s.lock.Lock()
@@ -167,37 +391,124 @@ func (s *CrawlerService) addCrawler(u *url.URL, p *models.Project, b *models.Bas
 	defer s.lock.Unlock()
 
 	if _, ok := s.crawlers[p.Id]; ok {
-		return nil, errors.New("project is already being crawled")
+		return nil, ErrAlreadyCrawling
+	}
+
+	scope, err := newCrawlScope(p)
+	if err != nil {
+		return nil, err
 	}
 
 	options := &crawler.Options{
-		CrawlLimit:      CrawlLimit,
+		CrawlLimit:      p.CrawlLimit,
+		MaxDepth:        p.MaxDepth,
+		Scope:           scope,
+		Normalizer:      newNormalizer(p),
 		IgnoreRobotsTxt: p.IgnoreRobotsTxt,
 		FollowNofollow:  p.FollowNofollow,
 		IncludeNoindex:  p.IncludeNoindex,
 		CrawlSitemap:    p.CrawlSitemap,
 		AllowSubdomains: p.AllowSubdomains,
+
+		Workers:              p.CrawlWorkers,
+		MinDelay:             time.Duration(p.CrawlMinDelay) * time.Millisecond,
+		MaxDelay:             time.Duration(p.CrawlMaxDelay) * time.Millisecond,
+		MaxRequestsPerSecond: p.CrawlMaxRPS,
+		Timeout:              time.Duration(p.CrawlTimeout) * time.Minute,
 	}
 
 	mainDomain := strings.TrimPrefix(u.Host, "www.")
 
+	// Projects saved before the request timeout was added use the default timeout.
+	timeout := p.RequestTimeout
+	if timeout == 0 {
+		timeout = ClientTimeout
+	}
+
 	httpClient := &http.Client{
-		Timeout: ClientTimeout * time.Second,
+		Timeout: time.Duration(timeout) * time.Second,
 		CheckRedirect: func(r *http.Request, via []*http.Request) error {
 			return http.ErrUseLastResponse
 		},
 	}
 
+	// The project's proxy takes precedence over the default one.
+	proxy := p.Proxy
+	if proxy == "" {
+		proxy = s.config.Proxy
+	}
+
+	proxyURL, err := ParseProxy(proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if proxyURL != nil {
+		transport = NewProxyTransport(proxyURL)
+	}
+
+	// TLS 1.0 and 1.1 are still accepted so the sites using them can be crawled and reported.
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS10}
+	httpClient.Transport = transport
+
+	if options.CrawlLimit == 0 {
+		options.CrawlLimit = CrawlLimit
+	}
+
+	// The admin may have lowered the ceilings after the project was saved.
+	if s.config.MaxCrawlLimit > 0 && options.CrawlLimit > s.config.MaxCrawlLimit {
+		options.CrawlLimit = s.config.MaxCrawlLimit
+	}
+
+	if s.config.MaxDepth > 0 && (options.MaxDepth == 0 || options.MaxDepth > s.config.MaxDepth) {
+		options.MaxDepth = s.config.MaxDepth
+	}
+
 	// Make sure the user agent is not empty
 	if p.UserAgent == "" {
 		p.UserAgent = s.config.Agent
 	}
 
+	headers, err := ParseCustomHeaders(p.CustomHeaders)
+	if err != nil {
+		return nil, err
+	}
+
+	cookies, err := ParseCustomCookies(p.CustomCookies)
+	if err != nil {
+		return nil, err
+	}
+
+	// The form login session is kept in a cookie jar that only lasts for this crawl.
+	var login *crawler.LoginOptions
+	if p.LoginURL != "" {
+		httpClient.Jar, err = cookiejar.New(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		login = &crawler.LoginOptions{
+			URL:             p.LoginURL,
+			UserField:       p.LoginUserField,
+			PassField:       p.LoginPassField,
+			User:            p.LoginUser,
+			Pass:            b.LoginPass,
+			SuccessMarker:   p.LoginSuccess,
+			LoggedOutMarker: p.LoginLoggedOut,
+		}
+	}
+
 	client := crawler.NewBasicClient(&crawler.ClientOptions{
 		UserAgent:        p.UserAgent,
 		BasicAuthDomains: []string{mainDomain, "www." + mainDomain},
 		AuthUser:         b.AuthUser,
 		AuthPass:         b.AuthPass,
+		CustomDomains:    []string{mainDomain, "www." + mainDomain},
+		Headers:          headers,
+		Cookies:          cookies,
+		Login:            login,
+		Retries:          p.RequestRetries,
 	}, httpClient)
 
 	// Creates a new crawler with the crawler's response handler.
@@ -206,6 +517,30 @@ func (s *CrawlerService) addCrawler(u *url.URL, p *models.Project, b *models.Bas
 	return s.crawlers[p.Id], nil
 }
 
+// listModeProject returns a copy of the project with the settings of a list mode crawl,
+// which doesn't load the URLs from the sitemaps.
+func listModeProject(p models.Project) *models.Project {
+	p.CrawlSitemap = false
+
+	return &p
+}
+
+// newCrawlScope returns the crawler's scope from the project's newline separated include and
+// exclude rules. It returns a nil scope if the project has no rules.
+func newCrawlScope(p *models.Project) (*crawler.Scope, error) {
+	if p.IncludeRules == "" && p.ExcludeRules == "" {
+		return nil, nil
+	}
+
+	return crawler.NewScope(strings.Split(p.IncludeRules, "\n"), strings.Split(p.ExcludeRules, "\n"))
+}
+
+// newNormalizer returns the URL normalizer with the project's comma separated list of
+// ignored query parameters.
+func newNormalizer(p *models.Project) *urlutils.Normalizer {
+	return urlutils.NewNormalizer(strings.Split(p.IgnoredParams, ","), p.RemoveIndex)
+}
+
 // RemoveCrawler removes a project's crawler from the crawlers map.
 func (s *CrawlerService) removeCrawler(p *models.Project) {
 	s.lock.Lock()
@@ -213,3 +548,59 @@ func (s *CrawlerService) removeCrawler(p *models.Project) {
 
 	delete(s.crawlers, p.Id)
 }
+
+// encodeCheckpoint serializes the crawler's checkpoint including the depth of the pending requests.
+func encodeCheckpoint(cp *crawler.Checkpoint) ([]byte, error) {
+	data := checkpointData{
+		Pending: make([]checkpointRequest, 0, len(cp.Pending)),
+		Seen:    cp.Seen,
+		Crawled: cp.Crawled,
+	}
+
+	for _, r := range cp.Pending {
+		cr := checkpointRequest{
+			URL:            r.URL.String(),
+			IgnoreDomain:   r.IgnoreDomain,
+			IgnoreMaxDepth: r.IgnoreMaxDepth,
+			Method:         r.Method,
+			Depth:          r.Depth,
+		}
+
+		data.Pending = append(data.Pending, cr)
+	}
+
+	return json.Marshal(data)
+}
+
+// decodeCheckpoint unserializes a checkpoint created with encodeCheckpoint.
+func decodeCheckpoint(b []byte) (*crawler.Checkpoint, error) {
+	data := checkpointData{}
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, err
+	}
+
+	cp := &crawler.Checkpoint{
+		Pending: make([]*crawler.RequestMessage, 0, len(data.Pending)),
+		Seen:    data.Seen,
+		Crawled: data.Crawled,
+	}
+
+	for _, cr := range data.Pending {
+		u, err := url.Parse(cr.URL)
+		if err != nil {
+			continue
+		}
+
+		r := &crawler.RequestMessage{
+			URL:            u,
+			IgnoreDomain:   cr.IgnoreDomain,
+			IgnoreMaxDepth: cr.IgnoreMaxDepth,
+			Method:         cr.Method,
+			Depth:          cr.Depth,
+		}
+
+		cp.Pending = append(cp.Pending, r)
+	}
+
+	return cp, nil
+}
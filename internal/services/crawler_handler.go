@@ -11,6 +11,7 @@ import (
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 
 	"github.com/antchfx/htmlquery"
 	"github.com/stjudewashere/seonaut/internal/crawler"
@@ -21,6 +22,7 @@ import (
 
 type CrawlerHandlerRepository interface {
 	SavePageReport(*models.PageReport, int64) (*models.PageReport, error)
+	SaveCertificate(*models.Certificate) error
 }
 
 type CrawlerHandler struct {
@@ -30,10 +32,6 @@ type CrawlerHandler struct {
 	externalLinksStatus map[string]int
 }
 
-type crawlerData struct {
-	Depth int
-}
-
 type Archiver interface {
 	AddRecord(*http.Response)
 }
@@ -57,6 +55,20 @@ func (s *CrawlerHandler) archiveWrapper(callback crawler.ResponseCallback, a Arc
 }
 
 func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project, c *crawler.Crawler) crawler.ResponseCallback {
+	// Hosts which TLS certificate has already been saved in this crawl.
+	certificateHosts := &sync.Map{}
+
+	// The extraction and search rules are validated when the project is saved.
+	extractors, err := ParseExtractors(p.Extractors)
+	if err != nil {
+		log.Printf("custom extractors error: %v", err)
+	}
+
+	searchRules, err := ParseSearchRules(p.CustomSearch)
+	if err != nil {
+		log.Printf("custom search error: %v", err)
+	}
+
 	return func(r *crawler.ResponseMessage) {
 		pageReport, htmlNode, err := s.buildPageReport(r)
 		if err != nil {
@@ -64,133 +76,71 @@ func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project
 			return
 		}
 
-		// Create a requestData object and increase the Depth value
-		// according to the data in the responseMessage's Data.
-		// If there's no crawlerData in the responseMessage the Depth value
-		// is -1 meaning it is undefined.
-		requestData := crawlerData{Depth: -1}
-		d, ok := r.Data.(crawlerData)
-		if ok {
-			requestData.Depth = d.Depth + 1
-		}
-
-		pageReport.TTFB = r.TTFB
-		pageReport.Depth = d.Depth
-		pageReport.BlockedByRobotstxt = r.Blocked
-		pageReport.InSitemap = r.InSitemap
-		pageReport.Crawled = !pageReport.Timeout && (p.FollowNofollow || !pageReport.Nofollow)
+		pageReport.Certificate = newCertificate(r.Certificate, crawl)
 
-		// Add link URLs to the crawler considering the nofollow attribute as well as
-		// the projects FollowNoFollow option. In case the URL is blocked by the robots.txt
-		// file a new blocked PageReport is saved. Both internal and external links
-		// are added as the crawler will discard the domains that are not allowed.
-		links := append(pageReport.Links, pageReport.ExternalLinks...)
-		for _, l := range links {
-			if (!pageReport.Nofollow && !l.NoFollow) || p.FollowNofollow {
-				err := c.AddRequest(&crawler.RequestMessage{URL: l.ParsedURL, Data: requestData})
-				if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-					s.saveBlockedPageReport(l.ParsedURL, crawl)
-					crawl.BlockedByRobotstxt++
-				}
-			}
+		// The URLs found in the page are one level deeper than the page. If the page's
+		// depth is unknown the depth of its URLs is unknown as well.
+		depth := -1
+		if r.Depth >= 0 {
+			depth = r.Depth + 1
 		}
 
-		// Add the indirect URLs such as canonicals, redirects or hreflang URLs to the crawler.
-		// In of the URL being blocked by the robots.txt save a new blocked PageReport.
-		for _, u := range s.getInderictURLs(pageReport) {
-			err := c.AddRequest(&crawler.RequestMessage{URL: u, Data: requestData})
-			if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-				s.saveBlockedPageReport(u, crawl)
-				crawl.BlockedByRobotstxt++
+		// Normalize the internal links so they match the URLs of the crawled pages.
+		for i := range pageReport.Links {
+			if pageReport.Links[i].ParsedURL != nil {
+				pageReport.Links[i].ParsedURL = c.NormalizeURL(pageReport.Links[i].ParsedURL)
+				pageReport.Links[i].URL = pageReport.Links[i].ParsedURL.String()
 			}
 		}
 
-		// Add the resource URLs to the crawler. If the URL is blocked in the robots.txt
-		// Save a new blocked PageReport.
-		for _, u := range s.getResourceURLs(pageReport) {
-			err := c.AddRequest(&crawler.RequestMessage{URL: u, IgnoreDomain: true, Data: requestData})
-			if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-				s.saveBlockedPageReport(u, crawl)
-				crawl.BlockedByRobotstxt++
-			}
+		// The redirect and hreflang URLs are kept as they were found, only the keys used
+		// to join them with the crawled pages are normalized.
+		pageReport.RedirectKey = normalizeURL(c, pageReport.ParsedURL, pageReport.RedirectURL)
+		for i := range pageReport.Hreflangs {
+			pageReport.Hreflangs[i].Key = normalizeURL(c, pageReport.ParsedURL, pageReport.Hreflangs[i].URL)
 		}
 
-		var cssURLs []*url.URL
-
-		// htmlquery panics if htmlNode is of type html.ErroNode
-		if strings.HasPrefix(strings.ToLower(pageReport.ContentType), "text/html") {
-			// Check preload links to add the urls to the crawler's queue.
-			preload, err := htmlquery.QueryAll(htmlNode, "//head/link[@rel=\"preload\"]/@href")
-			if err != nil {
-				log.Printf("error getting preload links %v %v", preload, err)
-			}
-
-			for _, preloadLink := range preload {
-				pl, err := urlutils.AbsoluteURL(htmlquery.SelectAttr(preloadLink, "href"), htmlNode, pageReport.ParsedURL)
-				if err != nil {
-					log.Printf("error getting preload link href %s %v", pl.String(), err)
-					continue
-				}
-
-				err = c.AddRequest(&crawler.RequestMessage{URL: pl, IgnoreDomain: true, Data: requestData})
-				if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-					s.saveBlockedPageReport(pl, crawl)
-					crawl.BlockedByRobotstxt++
-				}
-			}
-
-			// extract urls from style elements
-			styleTags, err := htmlquery.QueryAll(htmlNode, "//style")
-			if err != nil {
-				log.Printf("error getting style elements %v", err)
-			}
-
-			for _, st := range styleTags {
-				cssURLs = append(cssURLs, s.ExtractURLsFromCSS(htmlquery.InnerText(st))...)
-			}
-
-			// Extract urls from inline css
-			inlineStyleElements, err := htmlquery.QueryAll(htmlNode, "//*[@style]")
-			if err != nil {
-				log.Printf("error getting elements with style attribute: %v", err)
-			}
+		pageReport.TTFB = r.Timing.TTFB
+		pageReport.DNSLookup = r.Timing.DNSLookup
+		pageReport.TCPConnect = r.Timing.TCPConnect
+		pageReport.TLSHandshake = r.Timing.TLSHandshake
+		pageReport.Download = r.Timing.Download
+		pageReport.Protocol = r.Timing.Protocol
+		pageReport.TLSVersion = r.Timing.TLSVersion
+		pageReport.Attempts = max(r.Attempts, 1)
+		pageReport.Depth = r.Depth
+		pageReport.BlockedByRobotstxt = r.Blocked
+		pageReport.InSitemap = r.InSitemap
+		pageReport.Crawled = !pageReport.Timeout && (p.FollowNofollow || !pageReport.Nofollow)
 
-			for _, inlineStyleElement := range inlineStyleElements {
-				for _, attr := range inlineStyleElement.Attr {
-					if attr.Key == "style" {
-						cssURLs = append(cssURLs, s.ExtractURLsFromCSS(attr.Val)...)
-					}
-				}
-			}
+		// In list mode only the listed URLs are crawled, adding the redirect targets if
+		// the crawl is set to follow them.
+		switch crawl.Mode {
+		case models.CrawlModeSpider:
+			s.addPageURLs(c, crawl, p, r, pageReport, htmlNode, depth)
+		case models.CrawlModeListRedirects:
+			s.addRedirectURL(c, crawl, pageReport, depth)
 		}
 
-		// Extract URLs from the css files
-		if strings.HasPrefix(strings.ToLower(pageReport.ContentType), "text/css") {
-			body, err := io.ReadAll(r.Response.Body)
+		// Run the project's custom extraction and search rules on the HTML pages. The response
+		// body can be read again as it was replaced with a copy when the page was parsed.
+		// The search rules only run on 20x pages, so redirects and errors don't match the
+		// rules looking for pages without a pattern.
+		if (len(extractors) > 0 || len(searchRules) > 0) && pageReport.MediaType == "text/html" && r.Response != nil {
+			body, err := io.ReadAll(io.LimitReader(r.Response.Body, maxBodySize))
 			if err != nil {
-				log.Printf("failed to read response body: %v", err)
+				log.Printf("custom rules body error: %v", err)
 			}
-			cssURLs = append(cssURLs, s.ExtractURLsFromCSS(string(body))...)
-		}
 
-		// Add the extracted urls to the crawler's queue
-		for _, u := range cssURLs {
-			u = pageReport.ParsedURL.ResolveReference(u)
-
-			if u.Scheme != "https" && u.Scheme != "http" {
-				continue
-			}
-
-			err = c.AddRequest(&crawler.RequestMessage{URL: u, IgnoreDomain: true, Data: requestData})
-			if errors.Is(err, crawler.ErrBlockedByRobotstxt) {
-				s.saveBlockedPageReport(u, crawl)
-				crawl.BlockedByRobotstxt++
+			pageReport.Extractions = extract(extractors, htmlNode, body)
+			if pageReport.StatusCode >= 200 && pageReport.StatusCode < 300 {
+				pageReport.CustomSearch = search(searchRules, htmlNode, body)
 			}
 		}
 
 		// Check the external links if the project is set to do so.
 		if p.CheckExternalLinks {
-			s.checkExternalLinks(c.Client, pageReport)
+			s.checkExternalLinks(c, pageReport)
 		}
 
 		// Save the pageReport if it hasn't the noindex attribute or if the project
@@ -200,6 +150,7 @@ func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project
 		if !pageReport.Noindex || p.IncludeNoindex {
 			pageReport, err = s.repository.SavePageReport(pageReport, crawl.Id)
 			if err == nil {
+				s.saveCertificate(certificateHosts, pageReport)
 				headers := make(http.Header)
 				if r.Response != nil {
 					headers = r.Response.Header
@@ -223,6 +174,167 @@ func (s *CrawlerHandler) responseCallback(crawl *models.Crawl, p *models.Project
 	}
 }
 
+// addPageURLs adds the URLs found in the page to the crawler, including links, indirect URLs
+// such as canonicals or redirects, and resources such as images, scripts and CSS files.
+func (s *CrawlerHandler) addPageURLs(c *crawler.Crawler, crawl *models.Crawl, p *models.Project, r *crawler.ResponseMessage, pageReport *models.PageReport, htmlNode *html.Node, depth int) {
+	// Add link URLs to the crawler considering the nofollow attribute as well as
+	// the projects FollowNoFollow option. In case the URL is blocked by the robots.txt
+	// file a new blocked PageReport is saved. Both internal and external links
+	// are added as the crawler will discard the domains that are not allowed.
+	links := append(pageReport.Links, pageReport.ExternalLinks...)
+	for _, l := range links {
+		if (!pageReport.Nofollow && !l.NoFollow) || p.FollowNofollow {
+			s.addRequest(c, crawl, &crawler.RequestMessage{URL: l.ParsedURL, Depth: depth})
+		}
+	}
+
+	// Add the indirect URLs such as canonicals, redirects or hreflang URLs to the crawler.
+	// In of the URL being blocked by the robots.txt save a new blocked PageReport.
+	for _, u := range s.getInderictURLs(pageReport) {
+		s.addRequest(c, crawl, &crawler.RequestMessage{URL: u, Depth: depth})
+	}
+
+	// Add the resource URLs to the crawler. If the URL is blocked in the robots.txt
+	// Save a new blocked PageReport. Resources are not limited by the max depth so
+	// pages at the max depth are crawled with all their resources.
+	for _, u := range s.getResourceURLs(pageReport) {
+		s.addRequest(c, crawl, &crawler.RequestMessage{URL: u, IgnoreDomain: true, IgnoreMaxDepth: true, Depth: depth})
+	}
+
+	var cssURLs []*url.URL
+
+	// htmlquery panics if htmlNode is of type html.ErroNode
+	if strings.HasPrefix(strings.ToLower(pageReport.ContentType), "text/html") {
+		// Check preload links to add the urls to the crawler's queue.
+		preload, err := htmlquery.QueryAll(htmlNode, "//head/link[@rel=\"preload\"]/@href")
+		if err != nil {
+			log.Printf("error getting preload links %v %v", preload, err)
+		}
+
+		for _, preloadLink := range preload {
+			pl, err := urlutils.AbsoluteURL(htmlquery.SelectAttr(preloadLink, "href"), htmlNode, pageReport.ParsedURL)
+			if err != nil {
+				log.Printf("error getting preload link href %s %v", pl.String(), err)
+				continue
+			}
+
+			s.addRequest(c, crawl, &crawler.RequestMessage{URL: pl, IgnoreDomain: true, IgnoreMaxDepth: true, Depth: depth})
+		}
+
+		// extract urls from style elements
+		styleTags, err := htmlquery.QueryAll(htmlNode, "//style")
+		if err != nil {
+			log.Printf("error getting style elements %v", err)
+		}
+
+		for _, st := range styleTags {
+			cssURLs = append(cssURLs, s.ExtractURLsFromCSS(htmlquery.InnerText(st))...)
+		}
+
+		// Extract urls from inline css
+		inlineStyleElements, err := htmlquery.QueryAll(htmlNode, "//*[@style]")
+		if err != nil {
+			log.Printf("error getting elements with style attribute: %v", err)
+		}
+
+		for _, inlineStyleElement := range inlineStyleElements {
+			for _, attr := range inlineStyleElement.Attr {
+				if attr.Key == "style" {
+					cssURLs = append(cssURLs, s.ExtractURLsFromCSS(attr.Val)...)
+				}
+			}
+		}
+	}
+
+	// Extract URLs from the css files
+	if strings.HasPrefix(strings.ToLower(pageReport.ContentType), "text/css") {
+		body, err := io.ReadAll(r.Response.Body)
+		if err != nil {
+			log.Printf("failed to read response body: %v", err)
+		}
+		cssURLs = append(cssURLs, s.ExtractURLsFromCSS(string(body))...)
+	}
+
+	// Add the extracted urls to the crawler's queue
+	for _, u := range cssURLs {
+		u = pageReport.ParsedURL.ResolveReference(u)
+
+		if u.Scheme != "https" && u.Scheme != "http" {
+			continue
+		}
+
+		s.addRequest(c, crawl, &crawler.RequestMessage{URL: u, IgnoreDomain: true, IgnoreMaxDepth: true, Depth: depth})
+	}
+}
+
+// addRedirectURL adds the page's redirect target to the crawler, if there is one.
+func (s *CrawlerHandler) addRedirectURL(c *crawler.Crawler, crawl *models.Crawl, pageReport *models.PageReport, depth int) {
+	if pageReport.RedirectURL == "" {
+		return
+	}
+
+	u, err := pageReport.ParsedURL.Parse(pageReport.RedirectURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return
+	}
+
+	s.addRequest(c, crawl, &crawler.RequestMessage{URL: u, IgnoreDomain: true, Depth: depth})
+}
+
+// normalizeURL resolves the URL relative to the page's URL and normalizes it with the
+// crawler's normalizer. Empty URLs and URLs that can't be parsed are returned unchanged.
+func normalizeURL(c *crawler.Crawler, base *url.URL, u string) string {
+	if u == "" || base == nil {
+		return u
+	}
+
+	parsed, err := base.Parse(u)
+	if err != nil {
+		return u
+	}
+
+	return c.NormalizeURL(parsed).String()
+}
+
+// newCertificate returns the certificate model of the crawler's TLS certificate, or nil
+// if there is no certificate.
+func newCertificate(c *crawler.Certificate, crawl *models.Crawl) *models.Certificate {
+	if c == nil {
+		return nil
+	}
+
+	return &models.Certificate{
+		CrawlId:          crawl.Id,
+		Host:             c.Host,
+		Subject:          c.Subject,
+		Issuer:           c.Issuer,
+		NotBefore:        c.NotBefore,
+		NotAfter:         c.NotAfter,
+		DNSNames:         c.DNSNames,
+		Valid:            c.Valid,
+		HostnameMismatch: c.HostnameMismatch,
+		Error:            c.Error,
+	}
+}
+
+// saveCertificate saves the page report's TLS certificate if it is the first one
+// found in its host.
+func (s *CrawlerHandler) saveCertificate(hosts *sync.Map, pageReport *models.PageReport) {
+	c := pageReport.Certificate
+	if c == nil {
+		return
+	}
+
+	if _, saved := hosts.LoadOrStore(c.Host, true); saved {
+		return
+	}
+
+	c.PageReportId = pageReport.Id
+	if err := s.repository.SaveCertificate(c); err != nil {
+		log.Printf("crawler service: SaveCertificate: %v\n", err)
+	}
+}
+
 // buildPageReport builds a PageReport based on the responseMessage checking for Timeout errors.
 func (s *CrawlerHandler) buildPageReport(r *crawler.ResponseMessage) (*models.PageReport, *html.Node, error) {
 	// Check if the response caused an error and save a pageReport.
@@ -265,6 +377,23 @@ func (s *CrawlerHandler) buildPageReport(r *crawler.ResponseMessage) (*models.Pa
 	return pageReport, htmlNode, nil
 }
 
+// addRequest adds a new request to the crawler. If the URL is blocked by the robots.txt file
+// a new blocked PageReport is saved. Requests exceeding the project's max depth or out of the
+// project's scope are counted in the crawl so they are recorded as discovered but not crawled.
+func (s *CrawlerHandler) addRequest(c *crawler.Crawler, crawl *models.Crawl, r *crawler.RequestMessage) {
+	err := c.AddRequest(r)
+
+	switch {
+	case errors.Is(err, crawler.ErrBlockedByRobotstxt):
+		s.saveBlockedPageReport(r.URL, crawl)
+		crawl.BlockedByRobotstxt++
+	case errors.Is(err, crawler.ErrMaxDepth):
+		crawl.DepthLimited++
+	case errors.Is(err, crawler.ErrOutOfScope):
+		crawl.OutOfScope++
+	}
+}
+
 // saveBlockedPageReport saves a new PageReport with the specified URL and Crawl,
 // setting the blockedByRobotstxt field to true.
 func (s *CrawlerHandler) saveBlockedPageReport(u *url.URL, crawl *models.Crawl) {
@@ -320,7 +449,7 @@ func (s *CrawlerHandler) updateStatus(crawl *models.Crawl, pageReport *models.Pa
 // checkExternalLinks makes a HEAD request of the external links in a pageReport
 // and checks their status code. It stores the URL's status code in a map to
 // avoid requesting the same URL more than once.
-func (s *CrawlerHandler) checkExternalLinks(client crawler.Client, pageReport *models.PageReport) {
+func (s *CrawlerHandler) checkExternalLinks(c *crawler.Crawler, pageReport *models.PageReport) {
 	for n, l := range pageReport.ExternalLinks {
 		status, ok := s.externalLinksStatus[l.URL]
 		if ok {
@@ -329,7 +458,7 @@ func (s *CrawlerHandler) checkExternalLinks(client crawler.Client, pageReport *m
 		}
 
 		statusCode := -1
-		res, err := client.Head(l.URL)
+		res, err := c.Client.Head(c.Context(), l.URL)
 		if err == nil {
 			statusCode = res.Response.StatusCode
 		}
@@ -394,6 +523,12 @@ func (s *CrawlerHandler) getResourceURLs(p *models.PageReport) []*url.URL {
 		}
 	}
 
+	for _, t := range p.SocialTags {
+		if t.URL != "" {
+			resources = append(resources, t.URL)
+		}
+	}
+
 	for _, v := range resources {
 		t, err := url.Parse(v)
 		if err != nil {
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// cssToXPath translates a CSS selector into an XPath expression so it can be run with htmlquery.
+// It supports the type, universal, id, class and attribute selectors, the descendant, child and
+// sibling combinators, the :first-child and :last-child pseudo-classes and selector lists.
+// The selectors can end with the ::text or ::attr(name) pseudo-elements to extract the text
+// nodes or an attribute of the matched elements.
+func cssToXPath(selector string) (string, error) {
+	p := &cssParser{s: strings.TrimSpace(selector)}
+	if p.s == "" {
+		return "", errors.New("empty css selector")
+	}
+
+	paths := []string{}
+	for {
+		path, err := p.selector()
+		if err != nil {
+			return "", err
+		}
+
+		paths = append(paths, path)
+		if p.eof() {
+			break
+		}
+
+		if !p.consume(",") {
+			return "", p.error()
+		}
+	}
+
+	return strings.Join(paths, " | "), nil
+}
+
+type cssParser struct {
+	s string
+	i int
+}
+
+// Parses a complex selector, a sequence of compound selectors separated by combinators.
+func (p *cssParser) selector() (string, error) {
+	var b strings.Builder
+	b.WriteString("//")
+
+	p.skipSpace()
+	for {
+		step, err := p.compound()
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(step)
+
+		if strings.HasPrefix(p.s[p.i:], "::") {
+			pseudo, err := p.pseudoElement()
+			if err != nil {
+				return "", err
+			}
+
+			b.WriteString(pseudo)
+			p.skipSpace()
+
+			return b.String(), nil
+		}
+
+		space := p.skipSpace()
+		if p.eof() || p.peek() == ',' {
+			return b.String(), nil
+		}
+
+		switch p.peek() {
+		case '>':
+			b.WriteString("/")
+		case '+':
+			b.WriteString("/following-sibling::*[1]/self::")
+		case '~':
+			b.WriteString("/following-sibling::")
+		default:
+			if !space {
+				return "", p.error()
+			}
+
+			b.WriteString("//")
+			continue
+		}
+
+		p.i++
+		p.skipSpace()
+	}
+}
+
+// Parses a compound selector such as div#main.content[lang] into an XPath step.
+func (p *cssParser) compound() (string, error) {
+	start := p.i
+	tag := "*"
+	if p.consume("*") {
+		start = -1
+	} else if name := p.ident(); name != "" {
+		tag = strings.ToLower(name)
+	}
+
+	predicates := []string{}
+
+loop:
+	for !p.eof() {
+		switch p.peek() {
+		case '#':
+			p.i++
+			id := p.ident()
+			if id == "" {
+				return "", p.error()
+			}
+
+			literal, err := xpathLiteral(id)
+			if err != nil {
+				return "", err
+			}
+
+			predicates = append(predicates, "@id="+literal)
+		case '.':
+			p.i++
+			class := p.ident()
+			if class == "" {
+				return "", p.error()
+			}
+
+			predicates = append(predicates, fmt.Sprintf("contains(concat(' ', normalize-space(@class), ' '), ' %s ')", class))
+		case '[':
+			predicate, err := p.attribute()
+			if err != nil {
+				return "", err
+			}
+
+			predicates = append(predicates, predicate)
+		case ':':
+			if strings.HasPrefix(p.s[p.i:], "::") {
+				break loop
+			}
+
+			p.i++
+			switch strings.ToLower(p.ident()) {
+			case "first-child":
+				predicates = append(predicates, "not(preceding-sibling::*)")
+			case "last-child":
+				predicates = append(predicates, "not(following-sibling::*)")
+			default:
+				return "", p.error()
+			}
+		default:
+			break loop
+		}
+	}
+
+	if p.i == start {
+		return "", p.error()
+	}
+
+	for _, predicate := range predicates {
+		tag += "[" + predicate + "]"
+	}
+
+	return tag, nil
+}
+
+// Parses an attribute selector such as [rel="canonical"] into an XPath predicate.
+func (p *cssParser) attribute() (string, error) {
+	p.i++
+	p.skipSpace()
+
+	name := strings.ToLower(p.ident())
+	if name == "" {
+		return "", p.error()
+	}
+
+	p.skipSpace()
+	if p.consume("]") {
+		return "@" + name, nil
+	}
+
+	var operator string
+	for _, o := range []string{"=", "~=", "^=", "$=", "*="} {
+		if p.consume(o) {
+			operator = o
+			break
+		}
+	}
+
+	if operator == "" {
+		return "", p.error()
+	}
+
+	p.skipSpace()
+	value, err := p.value()
+	if err != nil {
+		return "", err
+	}
+
+	p.skipSpace()
+	if !p.consume("]") {
+		return "", p.error()
+	}
+
+	literal, err := xpathLiteral(value)
+	if err != nil {
+		return "", err
+	}
+
+	attr := "@" + name
+	switch operator {
+	case "~=":
+		return fmt.Sprintf("contains(concat(' ', normalize-space(%s), ' '), concat(' ', %s, ' '))", attr, literal), nil
+	case "^=":
+		return fmt.Sprintf("starts-with(%s, %s)", attr, literal), nil
+	case "$=":
+		return fmt.Sprintf("substring(%s, string-length(%s) - string-length(%s) + 1) = %s", attr, attr, literal, literal), nil
+	case "*=":
+		return fmt.Sprintf("contains(%s, %s)", attr, literal), nil
+	}
+
+	return attr + "=" + literal, nil
+}
+
+// Parses the ::text and ::attr(name) pseudo-elements.
+func (p *cssParser) pseudoElement() (string, error) {
+	p.i += 2
+	switch strings.ToLower(p.ident()) {
+	case "text":
+		return "/text()", nil
+	case "attr":
+		if !p.consume("(") {
+			return "", p.error()
+		}
+
+		p.skipSpace()
+		name := strings.ToLower(p.ident())
+		p.skipSpace()
+		if name == "" || !p.consume(")") {
+			return "", p.error()
+		}
+
+		return "/@" + name, nil
+	}
+
+	return "", p.error()
+}
+
+// Returns an attribute value, which can be a quoted string or an identifier.
+func (p *cssParser) value() (string, error) {
+	if p.eof() {
+		return "", p.error()
+	}
+
+	quote := p.peek()
+	if quote != '"' && quote != '\'' {
+		if v := p.ident(); v != "" {
+			return v, nil
+		}
+
+		return "", p.error()
+	}
+
+	end := strings.IndexByte(p.s[p.i+1:], quote)
+	if end < 0 {
+		return "", p.error()
+	}
+
+	v := p.s[p.i+1 : p.i+1+end]
+	p.i += end + 2
+
+	return v, nil
+}
+
+// Returns the identifier at the current position, or an empty string if there isn't one.
+func (p *cssParser) ident() string {
+	start := p.i
+	for _, r := range p.s[p.i:] {
+		if r != '-' && r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+
+		p.i += len(string(r))
+	}
+
+	return p.s[start:p.i]
+}
+
+// Skips the whitespace at the current position. It returns true if there was any.
+func (p *cssParser) skipSpace() bool {
+	start := p.i
+	for !p.eof() && unicode.IsSpace(rune(p.s[p.i])) {
+		p.i++
+	}
+
+	return p.i > start
+}
+
+// Consumes the string if it is at the current position.
+func (p *cssParser) consume(s string) bool {
+	if strings.HasPrefix(p.s[p.i:], s) {
+		p.i += len(s)
+		return true
+	}
+
+	return false
+}
+
+func (p *cssParser) peek() byte {
+	return p.s[p.i]
+}
+
+func (p *cssParser) eof() bool {
+	return p.i >= len(p.s)
+}
+
+func (p *cssParser) error() error {
+	return fmt.Errorf("css selector not supported at position %d: %s", p.i, p.s)
+}
+
+// Returns the string as an XPath literal. XPath 1.0 has no escape sequences, so it returns
+// an error if the string contains both single and double quotes.
+func xpathLiteral(s string) (string, error) {
+	if !strings.Contains(s, "'") {
+		return "'" + s + "'", nil
+	}
+
+	if !strings.Contains(s, `"`) {
+		return `"` + s + `"`, nil
+	}
+
+	return "", errors.New("css selector value with mixed quotes: " + s)
+}
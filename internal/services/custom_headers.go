@@ -0,0 +1,69 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/http/httpguts"
+)
+
+// Headers that can't be set as custom headers because they are managed by the crawler
+// or the HTTP client.
+var reservedHeaders = map[string]bool{
+	"Host":              true,
+	"User-Agent":        true,
+	"Cookie":            true,
+	"Content-Length":    true,
+	"Transfer-Encoding": true,
+	"Connection":        true,
+}
+
+// ParseCustomHeaders parses a newline separated list of headers in the "Name: value" format.
+// It returns an error if a header is not valid or if it is one of the reserved headers.
+func ParseCustomHeaders(s string) (http.Header, error) {
+	headers := http.Header{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if !ok || !httpguts.ValidHeaderFieldName(name) || !httpguts.ValidHeaderFieldValue(value) {
+			return nil, errors.New("header not valid: " + name)
+		}
+
+		if reservedHeaders[http.CanonicalHeaderKey(name)] {
+			return nil, errors.New("header is reserved: " + name)
+		}
+
+		headers.Add(name, value)
+	}
+
+	return headers, nil
+}
+
+// ParseCustomCookies parses a newline separated list of cookies in the "name=value" format.
+// It returns an error if any of the cookies is not valid.
+func ParseCustomCookies(s string) ([]*http.Cookie, error) {
+	cookies := []*http.Cookie{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(line, "=")
+		cookie := &http.Cookie{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)}
+		if !ok || cookie.Valid() != nil {
+			return nil, errors.New("cookie not valid: " + cookie.Name)
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	return cookies, nil
+}
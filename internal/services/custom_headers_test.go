@@ -0,0 +1,51 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// Test ParseCustomHeaders with valid and non valid headers.
+func TestParseCustomHeaders(t *testing.T) {
+	headers, err := services.ParseCustomHeaders("Accept-Language: es-ES\r\n\r\nx-forwarded-proto: https\nX-Token: a:b")
+	if err != nil {
+		t.Fatalf("ParseCustomHeaders error: %v", err)
+	}
+
+	want := map[string]string{
+		"Accept-Language":   "es-ES",
+		"X-Forwarded-Proto": "https",
+		"X-Token":           "a:b",
+	}
+
+	for name, value := range want {
+		if headers.Get(name) != value {
+			t.Errorf("ParseCustomHeaders: %s want %s got %s", name, value, headers.Get(name))
+		}
+	}
+
+	for _, s := range []string{"No colon", "Bad Name: value", "Host: example.com", "user-agent: bot"} {
+		if _, err := services.ParseCustomHeaders(s); err == nil {
+			t.Errorf("ParseCustomHeaders: %s should return an error", s)
+		}
+	}
+}
+
+// Test ParseCustomCookies with valid and non valid cookies.
+func TestParseCustomCookies(t *testing.T) {
+	cookies, err := services.ParseCustomCookies("consent=yes\r\n session = abc123 \n")
+	if err != nil {
+		t.Fatalf("ParseCustomCookies error: %v", err)
+	}
+
+	if len(cookies) != 2 || cookies[0].String() != "consent=yes" || cookies[1].String() != "session=abc123" {
+		t.Errorf("ParseCustomCookies: unexpected cookies %v", cookies)
+	}
+
+	for _, s := range []string{"novalue", "bad name=value", "=value"} {
+		if _, err := services.ParseCustomCookies(s); err == nil {
+			t.Errorf("ParseCustomCookies: %s should return an error", s)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"errors"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Custom search rule operators and scopes.
+const (
+	SearchContains    = "contains"
+	SearchNotContains = "not-contains"
+	SearchScopeHTML   = "html"
+	SearchScopeText   = "text"
+)
+
+type (
+	CustomSearchServiceRepository interface {
+		CountCustomSearchMatches(cid int64) map[string]int
+		GetNumberOfPagesForCustomSearch(cid int64, name string) int
+		FindCustomSearchPageReports(cid int64, p int, name string) []models.PageReport
+		FindAllPageReportsByCrawlIdAndCustomSearch(cid int64, name string) <-chan *models.PageReport
+	}
+
+	CustomSearchService struct {
+		repository CustomSearchServiceRepository
+	}
+
+	// SearchRule is a named custom search rule. The pattern is searched in the page's raw HTML
+	// code or in its body text, either as plain text or as a regular expression.
+	SearchRule struct {
+		Name     string
+		Contains bool // True if the rule matches the pages containing the pattern, false if it matches the ones lacking it.
+		Scope    string
+		Rule     string // The rule as it was written.
+		text     string
+		regex    *regexp.Regexp
+	}
+)
+
+func NewCustomSearchService(r CustomSearchServiceRepository) *CustomSearchService {
+	return &CustomSearchService{repository: r}
+}
+
+// ParseSearchRules parses a newline separated list of custom search rules in the "name operator
+// [scope] pattern" format. The operator is contains or not-contains, the optional scope is html,
+// the default, or text, and the pattern is a regular expression if it is enclosed in slashes.
+func ParseSearchRules(s string) ([]*SearchRule, error) {
+	rules := []*SearchRule{}
+	names := map[string]bool{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, errors.New("search rule not valid: " + line)
+		}
+
+		rule := &SearchRule{
+			Name:  fields[0],
+			Scope: SearchScopeHTML,
+			Rule:  line,
+		}
+
+		if len(rule.Name) > maxRuleName || !ruleNameRegex.MatchString(rule.Name) || names[rule.Name] {
+			return nil, errors.New("search rule name not valid: " + rule.Name)
+		}
+
+		names[rule.Name] = true
+
+		switch strings.ToLower(fields[1]) {
+		case SearchContains:
+			rule.Contains = true
+		case SearchNotContains:
+			rule.Contains = false
+		default:
+			return nil, errors.New("search rule operator not valid: " + fields[1])
+		}
+
+		pattern := strings.TrimSpace(fields[2])
+		if scope, p, ok := strings.Cut(pattern, " "); ok && (scope == SearchScopeHTML || scope == SearchScopeText) {
+			rule.Scope = scope
+			pattern = strings.TrimSpace(p)
+		}
+
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			regex, err := regexp.Compile(pattern[1 : len(pattern)-1])
+			if err != nil {
+				return nil, err
+			}
+
+			rule.regex = regex
+		} else if pattern != "" {
+			rule.text = pattern
+		} else {
+			return nil, errors.New("search rule pattern is empty: " + line)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// Match returns true if the page matches the rule, this is if the pattern is found in the rule's
+// scope and the rule's operator is contains, or if it is not found and the operator is not-contains.
+func (r *SearchRule) Match(body []byte, text string) bool {
+	var found bool
+	switch {
+	case r.Scope == SearchScopeText && r.regex != nil:
+		found = r.regex.MatchString(text)
+	case r.Scope == SearchScopeText:
+		found = strings.Contains(text, r.text)
+	case r.regex != nil:
+		found = r.regex.Match(body)
+	default:
+		found = strings.Contains(string(body), r.text)
+	}
+
+	return found == r.Contains
+}
+
+// Returns the names of the rules matched by the page. The body text is only extracted from
+// the HTML document if any of the rules is scoped to it.
+func search(rules []*SearchRule, htmlNode *html.Node, body []byte) []string {
+	var text string
+	for _, r := range rules {
+		if r.Scope == SearchScopeText {
+			text = bodyText(htmlNode)
+			break
+		}
+	}
+
+	matches := []string{}
+	for _, r := range rules {
+		if r.Match(body, text) {
+			matches = append(matches, r.Name)
+		}
+	}
+
+	return matches
+}
+
+// Returns the text content of the HTML document's body, without the scripts and styles.
+func bodyText(n *html.Node) string {
+	var b strings.Builder
+	var output func(*html.Node)
+	output = func(n *html.Node) {
+		if n.Type == html.ElementNode && (n.Data == "script" || n.Data == "style" || n.Data == "noscript" || n.Data == "head") {
+			return
+		}
+
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			output(c)
+		}
+	}
+
+	if n != nil {
+		output(n)
+	}
+
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// GetCustomSearchCounts returns the number of pages of the crawl matching each of the project's
+// custom search rules, followed by the rules that were removed from the project after the crawl.
+func (s *CustomSearchService) GetCustomSearchCounts(p *models.Project, crawlId int64) []models.CustomSearchCount {
+	matches := s.repository.CountCustomSearchMatches(crawlId)
+	counts := []models.CustomSearchCount{}
+
+	// The rules are validated when the project is saved.
+	rules, _ := ParseSearchRules(p.CustomSearch)
+	for _, r := range rules {
+		counts = append(counts, models.CustomSearchCount{Name: r.Name, Rule: r.Rule, Count: matches[r.Name]})
+		delete(matches, r.Name)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(matches)) {
+		counts = append(counts, models.CustomSearchCount{Name: name, Count: matches[name]})
+	}
+
+	return counts
+}
+
+// Returns a PaginatorView with the page reports matching the named custom search rule.
+func (s *CustomSearchService) GetPaginatedReportsBySearch(crawlId int64, currentPage int, name string) (models.PaginatorView, error) {
+	paginator := models.Paginator{
+		TotalPages:  s.repository.GetNumberOfPagesForCustomSearch(crawlId, name),
+		CurrentPage: currentPage,
+	}
+
+	if currentPage < 1 || (paginator.TotalPages > 0 && currentPage > paginator.TotalPages) {
+		return models.PaginatorView{}, errors.New("page out of bounds")
+	}
+
+	if currentPage < paginator.TotalPages {
+		paginator.NextPage = currentPage + 1
+	}
+
+	if currentPage > 1 {
+		paginator.PreviousPage = currentPage - 1
+	}
+
+	paginatorView := models.PaginatorView{
+		Paginator:   paginator,
+		PageReports: s.repository.FindCustomSearchPageReports(crawlId, currentPage, name),
+	}
+
+	return paginatorView, nil
+}
+
+// Returns a channel of the PageReports matching the named custom search rule.
+func (s *CustomSearchService) GetPageReportsBySearch(crawlId int64, name string) <-chan *models.PageReport {
+	return s.repository.FindAllPageReportsByCrawlIdAndCustomSearch(crawlId, name)
+}
@@ -0,0 +1,121 @@
+package services_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// Create a mock repository for the custom search service.
+type customSearchTestRepository struct {
+	counts map[string]int
+}
+
+func (r *customSearchTestRepository) CountCustomSearchMatches(cid int64) map[string]int {
+	return r.counts
+}
+
+func (r *customSearchTestRepository) GetNumberOfPagesForCustomSearch(cid int64, name string) int {
+	return 1
+}
+
+func (r *customSearchTestRepository) FindCustomSearchPageReports(cid int64, p int, name string) []models.PageReport {
+	return []models.PageReport{}
+}
+
+func (r *customSearchTestRepository) FindAllPageReportsByCrawlIdAndCustomSearch(cid int64, name string) <-chan *models.PageReport {
+	prStream := make(chan *models.PageReport)
+	close(prStream)
+
+	return prStream
+}
+
+// Test the custom search rules with plain text and regex patterns in the html and text scopes.
+func TestSearchRules(t *testing.T) {
+	rules, err := services.ParseSearchRules(strings.Join([]string{
+		"old-analytics contains ga.js",
+		"no-gtm not-contains /GTM-[A-Z0-9]+/",
+		"lorem CONTAINS text lorem ipsum",
+		"",
+		"price not-contains text /\\d+ €/",
+	}, "\n"))
+	if err != nil {
+		t.Fatalf("ParseSearchRules error: %v", err)
+	}
+
+	if len(rules) != 4 {
+		t.Fatalf("want 4 rules got %d", len(rules))
+	}
+
+	body := []byte(`<script src="https://www.google-analytics.com/ga.js"></script><p>Lorem ipsum</p>`)
+	text := "lorem ipsum dolor 10 €"
+
+	table := []struct {
+		scope    string
+		contains bool
+		expected bool
+	}{
+		{services.SearchScopeHTML, true, true},
+		{services.SearchScopeHTML, false, true},
+		{services.SearchScopeText, true, true},
+		{services.SearchScopeText, false, false},
+	}
+
+	for i, test := range table {
+		r := rules[i]
+		if r.Scope != test.scope || r.Contains != test.contains {
+			t.Errorf("rule %s: want scope %s and contains %v got %s and %v", r.Name, test.scope, test.contains, r.Scope, r.Contains)
+		}
+
+		if r.Match(body, text) != test.expected {
+			t.Errorf("rule %s: match should be %v", r.Name, test.expected)
+		}
+	}
+}
+
+// Test the custom search rules parser with rules that are not valid.
+func TestSearchRulesNotValid(t *testing.T) {
+	table := []string{
+		"name contains",
+		"name matches pattern",
+		"name contains /[a-z/",
+		"na me contains pattern",
+		"name contains a\nname contains b",
+	}
+
+	for _, rules := range table {
+		if _, err := services.ParseSearchRules(rules); err == nil {
+			t.Errorf("ParseSearchRules should fail with %q", rules)
+		}
+	}
+}
+
+// Test the custom search counts are sorted as in the project settings, followed by the
+// rules that were removed from the project.
+func TestGetCustomSearchCounts(t *testing.T) {
+	s := services.NewCustomSearchService(&customSearchTestRepository{
+		counts: map[string]int{"removed": 3, "b": 2, "a": 1},
+	})
+
+	project := &models.Project{CustomSearch: "b contains b\na contains a\nc not-contains c"}
+	counts := s.GetCustomSearchCounts(project, 1)
+
+	expected := []models.CustomSearchCount{
+		{Name: "b", Rule: "b contains b", Count: 2},
+		{Name: "a", Rule: "a contains a", Count: 1},
+		{Name: "c", Rule: "c not-contains c", Count: 0},
+		{Name: "removed", Count: 3},
+	}
+
+	if len(counts) != len(expected) {
+		t.Fatalf("want %d counts got %d", len(expected), len(counts))
+	}
+
+	for i := range expected {
+		if counts[i] != expected[i] {
+			t.Errorf("want %v got %v", expected[i], counts[i])
+		}
+	}
+}
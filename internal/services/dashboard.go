@@ -18,6 +18,7 @@ type (
 		CountScheme(int64) *models.SchemeCount
 		CountByNonCanonical(int64) int
 		GetStatusCodeByDepth(crawlId int64) []models.StatusCodeByDepth
+		GetTimingDistribution(crawlId int64) []models.TimingDistribution
 	}
 
 	DashboardService struct {
@@ -65,6 +66,12 @@ func (s *DashboardService) GetStatusCodeByDepth(crawlId int64) []models.StatusCo
 	return s.repository.GetStatusCodeByDepth(crawlId)
 }
 
+// GetTimingDistribution returns a slice of TimingDistribution models with the total number of
+// pagereports by duration range for each of the request phases.
+func (s *DashboardService) GetTimingDistribution(crawlId int64) []models.TimingDistribution {
+	return s.repository.GetTimingDistribution(crawlId)
+}
+
 // Returns a Chart containing the keys and values from the CountList.
 // It limits the slice to the chartLimit value.
 func newChart(c *models.CountList) *models.Chart {
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+type (
+	DiffServiceRepository interface {
+		CountDiffChanges(from, to int64, t string) int
+		GetNumberOfPagesForDiffChanges(from, to int64, t string) int
+		FindDiffChanges(from, to int64, t string, p int) []models.DiffChange
+		FindDiffIssues(from, to int64) []models.DiffIssue
+	}
+
+	DiffService struct {
+		repository DiffServiceRepository
+		cache      map[[2]int64]*models.CrawlDiff
+		lock       *sync.Mutex
+	}
+)
+
+// Max number of crawl diffs kept in the cache. The cache is emptied once it is full.
+const maxCachedDiffs = 100
+
+// DiffTypes contains the types of changes that are compared between two crawls
+// in the order they are displayed.
+var DiffTypes = []string{
+	models.DiffAdded,
+	models.DiffRemoved,
+	models.DiffStatusCode,
+	models.DiffIndexability,
+	models.DiffTitle,
+	models.DiffDescription,
+	models.DiffH1,
+	models.DiffCanonical,
+}
+
+// Error returned when the diff change type does not exist.
+var ErrDiffType = errors.New("diff type not valid")
+
+func NewDiffService(r DiffServiceRepository) *DiffService {
+	return &DiffService{
+		repository: r,
+		cache:      make(map[[2]int64]*models.CrawlDiff),
+		lock:       &sync.Mutex{},
+	}
+}
+
+// GetCrawlDiff returns a CrawlDiff with the number of changes of each type and the
+// issues introduced and resolved between the "from" and "to" crawls. Only finished crawls
+// are compared and their data doesn't change, so the diff is cached for each pair of crawls
+// instead of running all the count queries every time one of the changes pages is listed.
+func (s *DiffService) GetCrawlDiff(from, to *models.Crawl) *models.CrawlDiff {
+	key := [2]int64{from.Id, to.Id}
+
+	s.lock.Lock()
+	cached, ok := s.cache[key]
+	s.lock.Unlock()
+
+	if ok {
+		diff := *cached
+		diff.From = *from
+		diff.To = *to
+
+		return &diff
+	}
+
+	diff := &models.CrawlDiff{
+		From:   *from,
+		To:     *to,
+		Issues: s.repository.FindDiffIssues(from.Id, to.Id),
+	}
+
+	for _, t := range DiffTypes {
+		diff.Changes = append(diff.Changes, models.DiffCount{
+			Type:  t,
+			Count: s.repository.CountDiffChanges(from.Id, to.Id, t),
+		})
+	}
+
+	s.lock.Lock()
+	if len(s.cache) >= maxCachedDiffs {
+		clear(s.cache)
+	}
+	s.cache[key] = diff
+	s.lock.Unlock()
+
+	return diff
+}
+
+// GetPaginatedChanges returns a DiffPaginatorView with the changes of type "t"
+// corresponding to the current page.
+func (s *DiffService) GetPaginatedChanges(from, to *models.Crawl, t string, currentPage int) (models.DiffPaginatorView, error) {
+	if !s.validType(t) {
+		return models.DiffPaginatorView{}, ErrDiffType
+	}
+
+	paginator := models.Paginator{
+		TotalPages:  s.repository.GetNumberOfPagesForDiffChanges(from.Id, to.Id, t),
+		CurrentPage: currentPage,
+	}
+
+	if currentPage < 1 || currentPage > paginator.TotalPages {
+		return models.DiffPaginatorView{}, errors.New("page out of bounds")
+	}
+
+	if currentPage < paginator.TotalPages {
+		paginator.NextPage = currentPage + 1
+	}
+
+	if currentPage > 1 {
+		paginator.PreviousPage = currentPage - 1
+	}
+
+	return models.DiffPaginatorView{
+		Paginator: paginator,
+		Changes:   s.repository.FindDiffChanges(from.Id, to.Id, t, currentPage),
+	}, nil
+}
+
+// validType returns true if "t" is one of the available diff types.
+func (s *DiffService) validType(t string) bool {
+	for _, v := range DiffTypes {
+		if v == t {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+const (
+	test_diff_changes = 3
+	test_diff_pages   = 2
+)
+
+// Create a mock repository for the diff service.
+type diffTestRepository struct{}
+
+func (r *diffTestRepository) CountDiffChanges(from, to int64, t string) int {
+	return test_diff_changes
+}
+
+func (r *diffTestRepository) GetNumberOfPagesForDiffChanges(from, to int64, t string) int {
+	return test_diff_pages
+}
+
+func (r *diffTestRepository) FindDiffChanges(from, to int64, t string, p int) []models.DiffChange {
+	return []models.DiffChange{{Type: t, URL: test_url}}
+}
+
+func (r *diffTestRepository) FindDiffIssues(from, to int64) []models.DiffIssue {
+	return []models.DiffIssue{{ErrorType: "ERROR_EMPTY_TITLE", Introduced: 1}}
+}
+
+var diffService = services.NewDiffService(&diffTestRepository{})
+
+// Create a mock repository that counts the number of times the diff issues are loaded.
+type diffCountingTestRepository struct {
+	diffTestRepository
+	calls int
+}
+
+func (r *diffCountingTestRepository) FindDiffIssues(from, to int64) []models.DiffIssue {
+	r.calls++
+	return r.diffTestRepository.FindDiffIssues(from, to)
+}
+
+// TestGetCrawlDiff tests that the crawl diff contains the count of all the diff types.
+func TestGetCrawlDiff(t *testing.T) {
+	from := &models.Crawl{Id: test_previous_cid}
+	to := &models.Crawl{Id: test_cid}
+
+	diff := diffService.GetCrawlDiff(from, to)
+
+	if len(diff.Changes) != len(services.DiffTypes) {
+		t.Errorf("Changes %d != %d", len(diff.Changes), len(services.DiffTypes))
+	}
+
+	for i, c := range diff.Changes {
+		if c.Type != services.DiffTypes[i] || c.Count != test_diff_changes {
+			t.Errorf("Unexpected diff count %v", c)
+		}
+	}
+
+	if len(diff.Issues) != 1 {
+		t.Errorf("Issues %d != 1", len(diff.Issues))
+	}
+}
+
+// TestGetCrawlDiffCache tests the crawl diff is only loaded once for each pair of crawls.
+func TestGetCrawlDiffCache(t *testing.T) {
+	repository := &diffCountingTestRepository{}
+	s := services.NewDiffService(repository)
+
+	from := &models.Crawl{Id: test_previous_cid}
+	to := &models.Crawl{Id: test_cid}
+
+	s.GetCrawlDiff(from, to)
+	diff := s.GetCrawlDiff(from, to)
+	if repository.calls != 1 {
+		t.Errorf("Diff loaded %d times, want 1", repository.calls)
+	}
+
+	if diff.From.Id != from.Id || diff.To.Id != to.Id || len(diff.Changes) != len(services.DiffTypes) {
+		t.Errorf("Unexpected cached diff %v", diff)
+	}
+
+	s.GetCrawlDiff(to, from)
+	if repository.calls != 2 {
+		t.Errorf("Diff loaded %d times, want 2", repository.calls)
+	}
+}
+
+// TestGetPaginatedChanges tests the pagination and type validation of the crawl diff changes.
+func TestGetPaginatedChanges(t *testing.T) {
+	from := &models.Crawl{Id: test_previous_cid}
+	to := &models.Crawl{Id: test_cid}
+
+	table := []struct {
+		t        string
+		page     int
+		err      bool
+		next     int
+		previous int
+	}{
+		{models.DiffTitle, 1, false, 2, 0},
+		{models.DiffTitle, 2, false, 0, 1},
+		{models.DiffTitle, 0, true, 0, 0},
+		{models.DiffTitle, 3, true, 0, 0},
+		{"not_valid", 1, true, 0, 0},
+	}
+
+	for _, tc := range table {
+		v, err := diffService.GetPaginatedChanges(from, to, tc.t, tc.page)
+		if (err != nil) != tc.err {
+			t.Errorf("%s page %d: unexpected error value %v", tc.t, tc.page, err)
+			continue
+		}
+
+		if tc.err {
+			continue
+		}
+
+		if v.Paginator.NextPage != tc.next || v.Paginator.PreviousPage != tc.previous {
+			t.Errorf("%s page %d: unexpected paginator %v", tc.t, tc.page, v.Paginator)
+		}
+
+		if len(v.Changes) != 1 || v.Changes[0].Type != tc.t {
+			t.Errorf("%s page %d: unexpected changes %v", tc.t, tc.page, v.Changes)
+		}
+	}
+}
@@ -21,7 +21,13 @@ type (
 		ExportAudios(crawl *models.Crawl) <-chan *models.Audio
 		ExportVideos(crawl *models.Crawl) <-chan *models.ExportVideo
 		ExportHreflangs(crawl *models.Crawl) <-chan *models.ExportHreflang
+		ExportRedirectHops(crawl *models.Crawl) <-chan *models.ExportRedirectHop
+		ExportCookies(crawl *models.Crawl) <-chan *models.ExportCookie
+		ExportPageResources(crawl *models.Crawl) <-chan *models.ExportResource
+		ExportStructuredData(crawl *models.Crawl) <-chan *models.ExportStructuredData
 		ExportIssues(crawl *models.Crawl) <-chan *models.ExportIssue
+		ExportDiffChanges(from, to int64, t string) <-chan *models.DiffChange
+		ExportDiffIssues(from, to int64) <-chan *models.DiffChange
 	}
 
 	ExportTranslator interface {
@@ -239,6 +245,123 @@ func (e *Exporter) ExportHreflangs(f io.Writer, crawl *models.Crawl) {
 	w.Flush()
 }
 
+// Export the redirect chains as a CSV file with one row for every hop.
+func (e *Exporter) ExportRedirectChains(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Origin",
+		"Hop",
+		"URL",
+		"Status Code",
+		"Meta Refresh",
+		"Cross Scheme",
+		"Cross Host",
+	})
+
+	vStream := e.repository.ExportRedirectHops(crawl)
+
+	for v := range vStream {
+		w.Write([]string{
+			v.Origin,
+			strconv.Itoa(v.Position),
+			v.URL,
+			strconv.Itoa(v.StatusCode),
+			strconv.FormatBool(v.Refresh),
+			strconv.FormatBool(v.CrossScheme),
+			strconv.FormatBool(v.CrossHost),
+		})
+	}
+
+	w.Flush()
+}
+
+// Export the cookie inventory as a CSV file with one row for every page setting a cookie.
+func (e *Exporter) ExportCookies(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Name",
+		"Domain",
+		"Origin",
+		"Secure",
+		"HttpOnly",
+		"SameSite",
+	})
+
+	vStream := e.repository.ExportCookies(crawl)
+
+	for v := range vStream {
+		w.Write([]string{
+			v.Name,
+			v.Domain,
+			v.Origin,
+			strconv.FormatBool(v.Secure),
+			strconv.FormatBool(v.HttpOnly),
+			v.SameSite,
+		})
+	}
+
+	w.Flush()
+}
+
+// Export the third-party scripts and stylesheets inventory as a CSV file. It has one row for
+// every page loading a resource from a third-party domain, including the number of pages
+// that load resources from that domain.
+func (e *Exporter) ExportThirdPartyResources(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Domain",
+		"Type",
+		"Pages",
+		"Origin",
+		"URL",
+	})
+
+	inventory := BuildThirdPartyInventory(e.repository.ExportPageResources(crawl))
+
+	for _, d := range inventory {
+		for _, r := range d.Resources {
+			w.Write([]string{
+				d.Domain,
+				d.Type,
+				strconv.Itoa(d.Pages),
+				r.Origin,
+				r.URL,
+			})
+		}
+	}
+
+	w.Flush()
+}
+
+// Export the structured data as a CSV file with one row for every page containing structured
+// data, including its schema types, the number of items and how many of them are invalid.
+func (e *Exporter) ExportStructuredData(f io.Writer, crawl *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"URL",
+		"Types",
+		"Items",
+		"Invalid Items",
+	})
+
+	vStream := e.repository.ExportStructuredData(crawl)
+
+	for v := range vStream {
+		w.Write([]string{
+			v.Origin,
+			v.Types,
+			strconv.Itoa(v.Items),
+			strconv.Itoa(v.Invalid),
+		})
+	}
+
+	w.Flush()
+}
+
 // Export all issues as a CSV file. It includes the URL, issue type and priority
 func (e *Exporter) ExportAllIssues(lang string, f io.Writer, crawl *models.Crawl) {
 	w := csv.NewWriter(f)
@@ -270,12 +393,49 @@ func (e *Exporter) ExportAllIssues(lang string, f io.Writer, crawl *models.Crawl
 	w.Flush()
 }
 
+// ExportCrawlDiff exports the changes between the "from" and "to" crawls as a CSV file.
+// The changes are streamed from the database one type at a time, followed by the issues
+// that were introduced or resolved for each URL.
+func (e *Exporter) ExportCrawlDiff(lang string, f io.Writer, from, to *models.Crawl) {
+	w := csv.NewWriter(f)
+
+	w.Write([]string{
+		"Change",
+		"URL",
+		"Old",
+		"New",
+	})
+
+	for _, t := range DiffTypes {
+		for v := range e.repository.ExportDiffChanges(from.Id, to.Id, t) {
+			w.Write([]string{v.Type, v.URL, v.Old, v.New})
+		}
+	}
+
+	for v := range e.repository.ExportDiffIssues(from.Id, to.Id) {
+		oldIssue := v.Old
+		if oldIssue != "" {
+			oldIssue = e.translator.Trans(lang, oldIssue)
+		}
+
+		newIssue := v.New
+		if newIssue != "" {
+			newIssue = e.translator.Trans(lang, newIssue)
+		}
+
+		w.Write([]string{v.Type, v.URL, oldIssue, newIssue})
+	}
+
+	w.Flush()
+}
+
 // ExportPageReports exports the pagereport data for all the pageReports that are received
 // in the prStream channel. This export method is used to export all pageReports of crawl
-// or only the pageReports with specific issues in a crawl.
-func (e *Exporter) ExportPageReports(f io.Writer, prStream <-chan *models.PageReport) {
+// or only the pageReports with specific issues in a crawl. The values of the extractors
+// are added as additional columns.
+func (e *Exporter) ExportPageReports(f io.Writer, prStream <-chan *models.PageReport, extractors []string) {
 	writer := csv.NewWriter(f)
-	writer.Write([]string{
+	header := []string{
 		"Status Code",
 		"URL",
 		"Redirect URL",
@@ -293,10 +453,11 @@ func (e *Exporter) ExportPageReports(f io.Writer, prStream <-chan *models.PageRe
 		"Nº of words",
 		"Depth",
 		"TTFB",
-	})
+	}
+	writer.Write(append(header, extractors...))
 
 	for r := range prStream {
-		writer.Write([]string{
+		row := []string{
 			fmt.Sprintf("%d", r.StatusCode),
 			r.URL,
 			r.RedirectURL,
@@ -314,7 +475,8 @@ func (e *Exporter) ExportPageReports(f io.Writer, prStream <-chan *models.PageRe
 			strconv.Itoa(r.Words),
 			fmt.Sprintf("%d", r.Depth),
 			fmt.Sprintf("%d ms", r.TTFB),
-		})
+		}
+		writer.Write(append(row, r.ExtractedValues(extractors)...))
 
 		writer.Flush()
 	}
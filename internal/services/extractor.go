@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/antchfx/xpath"
+	"github.com/stjudewashere/seonaut/internal/models"
+
+	"golang.org/x/net/html"
+)
+
+// Custom extraction rule types.
+const (
+	ExtractorXPath = "xpath"
+	ExtractorCSS   = "css"
+	ExtractorRegex = "regex"
+)
+
+const (
+	maxRuleName        = 64 // Max length of the extractor and custom search rule names.
+	maxExtractorValues = 10 // Max number of values an extractor stores for each page.
+)
+
+var ruleNameRegex = regexp.MustCompile(`^[\p{L}\p{N}_\-.]+$`)
+
+// Extractor is a named custom extraction rule. XPath and CSS rules are run on the parsed
+// HTML document while the regex rules are run on the raw HTML code.
+type Extractor struct {
+	Name  string
+	Type  string
+	xpath *xpath.Expr
+	regex *regexp.Regexp
+}
+
+// ParseExtractors parses a newline separated list of extraction rules in the "name type expression"
+// format, where the type is one of xpath, css or regex. It returns an error if a name is not valid
+// or repeated, or if the expression can't be compiled.
+func ParseExtractors(s string) ([]*Extractor, error) {
+	extractors := []*Extractor{}
+	names := map[string]bool{}
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 || strings.TrimSpace(fields[2]) == "" {
+			return nil, errors.New("extractor not valid: " + line)
+		}
+
+		e := &Extractor{
+			Name: fields[0],
+			Type: strings.ToLower(fields[1]),
+		}
+
+		if len(e.Name) > maxRuleName || !ruleNameRegex.MatchString(e.Name) || names[e.Name] {
+			return nil, errors.New("extractor name not valid: " + e.Name)
+		}
+
+		names[e.Name] = true
+		expression := strings.TrimSpace(fields[2])
+
+		var err error
+		switch e.Type {
+		case ExtractorXPath:
+			e.xpath, err = xpath.Compile(expression)
+		case ExtractorCSS:
+			var xpathExpression string
+			xpathExpression, err = cssToXPath(expression)
+			if err == nil {
+				e.xpath, err = xpath.Compile(xpathExpression)
+			}
+		case ExtractorRegex:
+			e.regex, err = regexp.Compile(expression)
+		default:
+			err = errors.New("extractor type not supported: " + e.Type)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		extractors = append(extractors, e)
+	}
+
+	return extractors, nil
+}
+
+// Extract returns the values matched by the extractor in the HTML document or in its raw code.
+// The values are trimmed and the empty ones are skipped. Regex rules with capturing groups
+// return the first group instead of the whole match.
+func (e *Extractor) Extract(htmlNode *html.Node, body []byte) []string {
+	values := []string{}
+	add := func(v string) bool {
+		v = strings.Join(strings.Fields(v), " ")
+		if v != "" {
+			values = append(values, v)
+		}
+
+		return len(values) < maxExtractorValues
+	}
+
+	if e.regex != nil {
+		for _, m := range e.regex.FindAllSubmatch(body, maxExtractorValues) {
+			match := m[0]
+			if len(m) > 1 {
+				match = m[1]
+			}
+
+			if !add(string(match)) {
+				break
+			}
+		}
+
+		return values
+	}
+
+	if htmlNode == nil {
+		return values
+	}
+
+	// XPath expressions can evaluate to a node set or to a single string, number or boolean,
+	// for instance when using functions such as count() or normalize-space().
+	switch v := e.xpath.Evaluate(htmlquery.CreateXPathNavigator(htmlNode)).(type) {
+	case *xpath.NodeIterator:
+		for _, n := range htmlquery.QuerySelectorAll(htmlNode, e.xpath) {
+			if !add(htmlquery.InnerText(n)) {
+				break
+			}
+		}
+	case string:
+		add(v)
+	case float64:
+		add(strconv.FormatFloat(v, 'f', -1, 64))
+	case bool:
+		add(strconv.FormatBool(v))
+	}
+
+	return values
+}
+
+// Returns the values extracted from the page with each of the extractors.
+func extract(extractors []*Extractor, htmlNode *html.Node, body []byte) []models.Extraction {
+	extractions := []models.Extraction{}
+	for _, e := range extractors {
+		for _, v := range e.Extract(htmlNode, body) {
+			extractions = append(extractions, models.Extraction{Name: e.Name, Value: v})
+		}
+	}
+
+	return extractions
+}
@@ -0,0 +1,95 @@
+package services_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+const extractorTestHTML = `
+<html>
+<head>
+	<meta name="author" content="Jane Doe">
+</head>
+<body>
+	<ul class="breadcrumb nav">
+		<li><a href="/">Home</a></li>
+		<li><a href="/shop">Shop</a></li>
+		<li class="active">Product</li>
+	</ul>
+	<div id="product" class="product">
+		<span class="price" data-currency="EUR">10.00 €</span>
+		<span class="sku">sku-1234</span>
+		<a href="/related" rel="related nofollow">Related</a>
+	</div>
+</body>
+</html>`
+
+// Test the extractors with XPath, CSS selector and regex rules.
+func TestExtractors(t *testing.T) {
+	rules := strings.Join([]string{
+		"author xpath //meta[@name='author']/@content",
+		"links xpath count(//a)",
+		"breadcrumb css ul.breadcrumb > li",
+		"first css .breadcrumb li:first-child a::text",
+		"currency css #product span[data-currency]::attr(data-currency)",
+		"related css a[rel~=nofollow], a[href^='/shop']",
+		"sku regex sku-(\\d+)",
+		"title css title",
+	}, "\n")
+
+	extractors, err := services.ParseExtractors(rules)
+	if err != nil {
+		t.Fatalf("ParseExtractors error: %v", err)
+	}
+
+	doc, err := htmlquery.Parse(strings.NewReader(extractorTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"author":     {"Jane Doe"},
+		"links":      {"3"},
+		"breadcrumb": {"Home", "Shop", "Product"},
+		"first":      {"Home"},
+		"currency":   {"EUR"},
+		"related":    {"Shop", "Related"},
+		"sku":        {"1234"},
+		"title":      {},
+	}
+
+	// Selector lists are unions in XPath, so the values are sorted before comparing them.
+	for _, e := range extractors {
+		values := e.Extract(doc, []byte(extractorTestHTML))
+		slices.Sort(values)
+		slices.Sort(expected[e.Name])
+		if strings.Join(values, ",") != strings.Join(expected[e.Name], ",") {
+			t.Errorf("Extractor %s: %v != %v", e.Name, values, expected[e.Name])
+		}
+	}
+}
+
+// Test ParseExtractors with non valid rules.
+func TestParseExtractorsErrors(t *testing.T) {
+	table := []string{
+		"price",
+		"price css",
+		"price json $.price",
+		"price xpath //span[",
+		"price regex (",
+		"price css div >",
+		"price css div:hover",
+		"price css .price\nprice xpath //span",
+		"the price css .price",
+	}
+
+	for _, rules := range table {
+		if _, err := services.ParseExtractors(rules); err == nil {
+			t.Errorf("ParseExtractors: %q should return an error", rules)
+		}
+	}
+}
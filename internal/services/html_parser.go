@@ -69,6 +69,7 @@ func NewHTMLParser(u *url.URL, status int, headers *http.Header, body []byte, co
 	}
 
 	pageReport.MediaType, _, _ = mime.ParseMediaType(pageReport.ContentType)
+	pageReport.Cookies = parser.headersCookies()
 
 	if pageReport.StatusCode >= http.StatusMultipleChoices && pageReport.StatusCode < http.StatusBadRequest {
 		pageReport.RedirectURL = parser.headersLocation()
@@ -95,6 +96,8 @@ func NewHTMLParser(u *url.URL, status int, headers *http.Header, body []byte, co
 		pageReport.Videos = parser.htmlVideos()
 		pageReport.Scripts = parser.htmlScripts()
 		pageReport.Styles = parser.htmlStyles()
+		pageReport.StructuredData = parser.htmlStructuredData()
+		pageReport.SocialTags = parser.htmlSocialTags()
 
 		pictures := parser.htmlPictures()
 		pageReport.Images = append(pageReport.Images, pictures...)
@@ -113,6 +116,8 @@ func NewHTMLParser(u *url.URL, status int, headers *http.Header, body []byte, co
 			pageReport.Words = countWords(bnode)
 		}
 
+		pageReport.SimHash = simHash(parser.htmlMainText())
+
 		pageReport.BodyHash, err = hashString(body)
 		if err != nil {
 			log.Printf("body hashString URL: %s\nError %v", u.String(), err)
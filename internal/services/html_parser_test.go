@@ -8,6 +8,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/stjudewashere/seonaut/internal/models"
 	"github.com/stjudewashere/seonaut/internal/services"
 )
 
@@ -601,3 +602,150 @@ func TestBaseRelativeURL(t *testing.T) {
 		t.Errorf("Link with base URL does not match, got %s", pageReport.ExternalLinks[0].URL)
 	}
 }
+
+func TestSetCookieHeaders(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	body := []byte("<html>")
+	headers := http.Header{
+		"Content-Type": []string{"text/html"},
+		"Set-Cookie": []string{
+			"session=abc; Path=/; Secure; HttpOnly; SameSite=Strict",
+			"tracking=1; Domain=.example.org",
+		},
+	}
+
+	pageReport, _, err := services.NewHTMLParser(u, 200, &headers, body, int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pageReport.Cookies) != 2 {
+		t.Fatalf("Cookies %d != 2", len(pageReport.Cookies))
+	}
+
+	session := pageReport.Cookies[0]
+	if session.Name != "session" || session.Domain != "example.com" || !session.Secure || !session.HttpOnly || session.SameSite != "Strict" {
+		t.Errorf("Unexpected session cookie %+v", session)
+	}
+
+	tracking := pageReport.Cookies[1]
+	if tracking.Name != "tracking" || tracking.Domain != "example.org" || tracking.Secure || tracking.HttpOnly || tracking.SameSite != "" {
+		t.Errorf("Unexpected tracking cookie %+v", tracking)
+	}
+}
+
+func TestStructuredData(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	body := []byte(`
+		<html>
+		<head>
+			<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Organization", "name": "Example"}</script>
+			<script type="application/ld+json">{"@context": "https://schema.org", "@graph": [{"@type": "WebPage"}, {"@type": ["Article", "https://schema.org/NewsArticle"]}]}</script>
+			<script type="application/ld+json">{"@type": "Product",}</script>
+		</head>
+		<body>
+			<div itemscope itemtype="https://schema.org/Product">
+				<h1 itemprop="name">Example   product</h1>
+				<div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+					<meta itemprop="price" content="10.00">
+				</div>
+			</div>
+		</body>
+		</html>`)
+
+	headers := http.Header{"Content-Type": []string{"text/html"}}
+
+	pageReport, _, err := services.NewHTMLParser(u, 200, &headers, body, int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		format string
+		t      string
+		err    bool
+	}{
+		{"json-ld", "Organization", false},
+		{"json-ld", "WebPage", false},
+		{"json-ld", "Article,NewsArticle", false},
+		{"json-ld", "", true},
+		{"microdata", "Product", false},
+	}
+
+	if len(pageReport.StructuredData) != len(expected) {
+		t.Fatalf("StructuredData %d != %d", len(pageReport.StructuredData), len(expected))
+	}
+
+	for i, e := range expected {
+		d := pageReport.StructuredData[i]
+		if d.Format != e.format || d.Type != e.t || (d.Error != "") != e.err {
+			t.Errorf("StructuredData %d: %s %s %q", i, d.Format, d.Type, d.Error)
+		}
+	}
+
+	microdata := `{"@type":"https://schema.org/Product","name":"Example product","offers":{"@type":"https://schema.org/Offer","price":"10.00"}}`
+	if pageReport.StructuredData[4].Raw != microdata {
+		t.Errorf("Microdata raw %s != %s", pageReport.StructuredData[4].Raw, microdata)
+	}
+}
+
+func TestSocialTags(t *testing.T) {
+	u, err := url.Parse(testURL)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	body := []byte(`
+		<html>
+		<head>
+			<title>Page title</title>
+			<meta property="og:title" content="OG title">
+			<meta property="OG:Image" content="/images/share.jpg">
+			<meta name="twitter:card" content="summary_large_image">
+			<meta name="twitter:image" content="https://cdn.example.com/card.jpg">
+			<meta name="description" content="Page description">
+		</head>
+		<body></body>
+		</html>`)
+
+	headers := http.Header{"Content-Type": []string{"text/html"}}
+
+	pageReport, _, err := services.NewHTMLParser(u, 200, &headers, body, int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []models.SocialTag{
+		{Property: "og:title", Content: "OG title"},
+		{Property: "og:image", Content: "/images/share.jpg", URL: "https://example.com/images/share.jpg"},
+		{Property: "twitter:card", Content: "summary_large_image"},
+		{Property: "twitter:image", Content: "https://cdn.example.com/card.jpg", URL: "https://cdn.example.com/card.jpg"},
+	}
+
+	if len(pageReport.SocialTags) != len(expected) {
+		t.Fatalf("SocialTags %d != %d", len(pageReport.SocialTags), len(expected))
+	}
+
+	for i, e := range expected {
+		if pageReport.SocialTags[i] != e {
+			t.Errorf("SocialTag %d: %+v != %+v", i, pageReport.SocialTags[i], e)
+		}
+	}
+
+	preview := models.NewSocialPreview(pageReport)
+	if preview.Title != "OG title" || preview.Description != "Page description" {
+		t.Errorf("SocialPreview title %q description %q", preview.Title, preview.Description)
+	}
+
+	if preview.Image != "https://example.com/images/share.jpg" || preview.SiteName != "example.com" {
+		t.Errorf("SocialPreview image %q site name %q", preview.Image, preview.SiteName)
+	}
+}
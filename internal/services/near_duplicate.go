@@ -0,0 +1,238 @@
+package services
+
+import (
+	"cmp"
+	"log"
+	"math"
+	"math/bits"
+	"math/rand"
+	"slices"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+const (
+	defaultNearDuplicateSimilarity = 95
+	minNearDuplicateSimilarity     = 80
+
+	// Number of fingerprint bits used as the key of each hash table.
+	nearDuplicateKeyBits = 12
+
+	// Probability of finding a pair of pages at the lowest similarity in any of the hash tables.
+	nearDuplicateRecall = 0.99
+
+	// Max number of pages each page is compared with in a hash table bucket.
+	maxNearDuplicateComparisons = 32
+)
+
+type (
+	NearDuplicateServiceRepository interface {
+		FindFingerprintedPageReports(cid int64) []models.PageReport
+		SaveNearDuplicates(cid int64, duplicates []models.NearDuplicate) error
+	}
+
+	NearDuplicateService struct {
+		repository NearDuplicateServiceRepository
+		similarity int
+	}
+)
+
+// NewNearDuplicateService returns a NearDuplicateService reporting the pages with a similarity
+// percentage equal or above the given one. It uses the default if the similarity is not set
+// and it is never lower than minNearDuplicateSimilarity.
+func NewNearDuplicateService(r NearDuplicateServiceRepository, similarity int) *NearDuplicateService {
+	if similarity <= 0 {
+		similarity = defaultNearDuplicateSimilarity
+	}
+
+	similarity = min(max(similarity, minNearDuplicateSimilarity), 100)
+
+	return &NearDuplicateService{repository: r, similarity: similarity}
+}
+
+// SaveNearDuplicates finds the near-duplicate pages of the crawl and stores their closest match.
+func (s *NearDuplicateService) SaveNearDuplicates(crawl *models.Crawl) {
+	duplicates := s.FindNearDuplicates(s.repository.FindFingerprintedPageReports(crawl.Id))
+	if err := s.repository.SaveNearDuplicates(crawl.Id, duplicates); err != nil {
+		log.Printf("SaveNearDuplicates: cid %d %v", crawl.Id, err)
+	}
+}
+
+// FindNearDuplicates returns the page reports with a SimHash fingerprint similar to the one of
+// any other page report. Pages with the same body are left out, as they are already reported
+// as duplicated content.
+//
+// Comparing every pair of pages doesn't scale, so the fingerprints are added to several hash
+// tables, each one keyed by a different random sample of the fingerprint bits, and only the ones
+// sharing a bucket are compared. The number of tables is enough to find a pair of pages at the
+// lowest similarity with a probability of nearDuplicateRecall. Pages sharing a template can fill
+// a bucket, so each page is only compared with its closest pages in the bucket order.
+func (s *NearDuplicateService) FindNearDuplicates(pageReports []models.PageReport) []models.NearDuplicate {
+	// Pages with the same fingerprint are grouped so each fingerprint is only compared once.
+	groups := [][]int{}
+	fingerprints := []uint64{}
+	groupIndex := map[uint64]int{}
+	for i, p := range pageReports {
+		g, ok := groupIndex[p.SimHash]
+		if !ok {
+			g = len(groups)
+			groupIndex[p.SimHash] = g
+			groups = append(groups, []int{})
+			fingerprints = append(fingerprints, p.SimHash)
+		}
+
+		groups[g] = append(groups[g], i)
+	}
+
+	// The closest group to each group and its similarity.
+	closest := make([]int, len(groups))
+	similarity := make([]int, len(groups))
+	for g := range closest {
+		closest[g] = -1
+	}
+
+	clusters := newUnionFind(len(groups))
+
+	compare := func(a, b int) {
+		sim := SimHashSimilarity(fingerprints[a], fingerprints[b])
+		if sim < s.similarity {
+			return
+		}
+
+		clusters.union(a, b)
+		if sim > similarity[a] {
+			closest[a], similarity[a] = b, sim
+		}
+
+		if sim > similarity[b] {
+			closest[b], similarity[b] = a, sim
+		}
+	}
+
+	// The bits are sampled with a fixed seed so the results are the same in every crawl.
+	rnd := rand.New(rand.NewSource(1))
+	for t := range nearDuplicateTables(s.similarity) {
+		positions := rnd.Perm(64)[:nearDuplicateKeyBits]
+		buckets := map[uint64][]int{}
+		for g, f := range fingerprints {
+			key := uint64(0)
+			for _, p := range positions {
+				key = key<<1 | (f>>p)&1
+			}
+
+			buckets[key] = append(buckets[key], g)
+		}
+
+		for _, bucket := range buckets {
+			// Large buckets are sorted so the fingerprints sharing more bits are next to each
+			// other, rotating them so the order is different in every table.
+			if len(bucket) > maxNearDuplicateComparisons {
+				slices.SortFunc(bucket, func(a, b int) int {
+					return cmp.Compare(bits.RotateLeft64(fingerprints[a], t), bits.RotateLeft64(fingerprints[b], t))
+				})
+			}
+
+			for i, a := range bucket {
+				for _, b := range bucket[i+1 : min(i+1+maxNearDuplicateComparisons, len(bucket))] {
+					compare(a, b)
+				}
+			}
+		}
+	}
+
+	duplicates := []models.NearDuplicate{}
+	roots := []int{}
+	for g, members := range groups {
+		// Pages with the same fingerprint but a different body are a 100% match.
+		first := pageReports[members[0]]
+		var other *models.PageReport
+		for _, m := range members[1:] {
+			if pageReports[m].BodyHash != first.BodyHash {
+				other = &pageReports[m]
+				break
+			}
+		}
+
+		for _, m := range members {
+			p := pageReports[m]
+			d := models.NearDuplicate{PageReportId: p.Id, Similarity: 100}
+			switch {
+			case p.BodyHash != first.BodyHash:
+				d.Match.Id = first.Id
+			case other != nil:
+				d.Match.Id = other.Id
+			case closest[g] >= 0:
+				d.Match.Id = pageReports[groups[closest[g]][0]].Id
+				d.Similarity = similarity[g]
+			default:
+				continue
+			}
+
+			duplicates = append(duplicates, d)
+			roots = append(roots, clusters.find(g))
+		}
+	}
+
+	// Each cluster is identified by the lowest page report id of its near-duplicate pages.
+	clusterIds := map[int]int64{}
+	for i, d := range duplicates {
+		if id, ok := clusterIds[roots[i]]; !ok || d.PageReportId < id {
+			clusterIds[roots[i]] = d.PageReportId
+		}
+	}
+
+	for i := range duplicates {
+		duplicates[i].Cluster = clusterIds[roots[i]]
+	}
+
+	return duplicates
+}
+
+// nearDuplicateTables returns the number of hash tables needed to find a pair of pages at the
+// lowest similarity with a probability of nearDuplicateRecall. A pair of fingerprints that differ
+// in d bits shares a table's key if none of the d bits is sampled for it.
+func nearDuplicateTables(similarity int) int {
+	distance := 64 * (100 - similarity) / 100
+	p := 1.0
+	for i := range nearDuplicateKeyBits {
+		p *= float64(64-distance-i) / float64(64-i)
+	}
+
+	if p >= 1 {
+		return 1
+	}
+
+	return int(math.Ceil(math.Log(1-nearDuplicateRecall) / math.Log(1-p)))
+}
+
+// unionFind is a disjoint set used to group the near-duplicate pages in clusters.
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	u := &unionFind{parent: make([]int, n)}
+	for i := range u.parent {
+		u.parent[i] = i
+	}
+
+	return u
+}
+
+// Returns the root of the set containing i, compressing the path to it.
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+
+	return i
+}
+
+// Merges the sets containing a and b.
+func (u *unionFind) union(a, b int) {
+	ra, rb := u.find(a), u.find(b)
+	if ra != rb {
+		u.parent[rb] = ra
+	}
+}
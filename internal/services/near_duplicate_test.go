@@ -0,0 +1,187 @@
+package services_test
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// Create a mock repository for the near-duplicate service.
+type nearDuplicateTestRepository struct {
+	pageReports []models.PageReport
+	saved       []models.NearDuplicate
+}
+
+func (r *nearDuplicateTestRepository) FindFingerprintedPageReports(cid int64) []models.PageReport {
+	return r.pageReports
+}
+
+func (r *nearDuplicateTestRepository) SaveNearDuplicates(cid int64, duplicates []models.NearDuplicate) error {
+	r.saved = duplicates
+	return nil
+}
+
+// Test the near-duplicates are found by comparing the fingerprints, and that the pages with the
+// same body are only reported if they are also similar to a page with a different body.
+func TestFindNearDuplicates(t *testing.T) {
+	f := uint64(0xF0F0F0F0F0F0F0F0)
+	repository := &nearDuplicateTestRepository{
+		pageReports: []models.PageReport{
+			{Id: 1, SimHash: f, BodyHash: "a"},
+			{Id: 2, SimHash: f ^ 0x7, BodyHash: "b"},
+			{Id: 3, SimHash: f ^ 0xFF, BodyHash: "c"},
+			{Id: 4, SimHash: ^f, BodyHash: "d"},
+			{Id: 5, SimHash: ^f, BodyHash: "d"},
+			{Id: 6, SimHash: ^f, BodyHash: "e"},
+			{Id: 7, SimHash: 0x123456789ABCDEF, BodyHash: "f"},
+			{Id: 8, SimHash: 0x123456789ABCDEF, BodyHash: "f"},
+		},
+	}
+
+	table := []struct {
+		similarity int
+		expected   map[int64]models.NearDuplicate
+	}{
+		{
+			similarity: 0,
+			expected: map[int64]models.NearDuplicate{
+				1: {PageReportId: 1, Match: models.PageReport{Id: 2}, Similarity: 95, Cluster: 1},
+				2: {PageReportId: 2, Match: models.PageReport{Id: 1}, Similarity: 95, Cluster: 1},
+				4: {PageReportId: 4, Match: models.PageReport{Id: 6}, Similarity: 100, Cluster: 4},
+				5: {PageReportId: 5, Match: models.PageReport{Id: 6}, Similarity: 100, Cluster: 4},
+				6: {PageReportId: 6, Match: models.PageReport{Id: 4}, Similarity: 100, Cluster: 4},
+			},
+		},
+		{
+			similarity: 85,
+			expected: map[int64]models.NearDuplicate{
+				1: {PageReportId: 1, Match: models.PageReport{Id: 2}, Similarity: 95, Cluster: 1},
+				2: {PageReportId: 2, Match: models.PageReport{Id: 1}, Similarity: 95, Cluster: 1},
+				3: {PageReportId: 3, Match: models.PageReport{Id: 2}, Similarity: 92, Cluster: 1},
+				4: {PageReportId: 4, Match: models.PageReport{Id: 6}, Similarity: 100, Cluster: 4},
+				5: {PageReportId: 5, Match: models.PageReport{Id: 6}, Similarity: 100, Cluster: 4},
+				6: {PageReportId: 6, Match: models.PageReport{Id: 4}, Similarity: 100, Cluster: 4},
+			},
+		},
+	}
+
+	for _, test := range table {
+		s := services.NewNearDuplicateService(repository, test.similarity)
+		s.SaveNearDuplicates(&models.Crawl{Id: 1})
+
+		if len(repository.saved) != len(test.expected) {
+			t.Fatalf("similarity %d: want %d near-duplicates got %d", test.similarity, len(test.expected), len(repository.saved))
+		}
+
+		for _, d := range repository.saved {
+			e := test.expected[d.PageReportId]
+			if d.Match.Id != e.Match.Id || d.Similarity != e.Similarity || d.Cluster != e.Cluster {
+				t.Errorf("similarity %d: page %d want match %d %d%% cluster %d got match %d %d%% cluster %d",
+					test.similarity, d.PageReportId, e.Match.Id, e.Similarity, e.Cluster, d.Match.Id, d.Similarity, d.Cluster)
+			}
+		}
+	}
+}
+
+// Test the near-duplicates are found in a large crawl of pages sharing a template, where most
+// fingerprints are close to each other but below the similarity threshold.
+func TestFindNearDuplicatesLargeCrawl(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	flip := func(f uint64, n int) uint64 {
+		for _, b := range rnd.Perm(64)[:n] {
+			f ^= 1 << b
+		}
+
+		return f
+	}
+
+	template := rnd.Uint64()
+	pageReports := []models.PageReport{}
+	for i := range 20000 {
+		pageReports = append(pageReports, models.PageReport{
+			Id:       int64(i + 1),
+			SimHash:  flip(template, 10),
+			BodyHash: fmt.Sprint(i),
+		})
+	}
+
+	// Every tenth page gets a near-duplicate with a similarity of at least 90%.
+	planted := map[int64]bool{}
+	for i := 0; i < 20000; i += 10 {
+		id := int64(len(pageReports) + 1)
+		pageReports = append(pageReports, models.PageReport{
+			Id:       id,
+			SimHash:  flip(pageReports[i].SimHash, 6),
+			BodyHash: fmt.Sprint(id),
+		})
+		planted[pageReports[i].Id], planted[id] = true, true
+	}
+
+	s := services.NewNearDuplicateService(&nearDuplicateTestRepository{}, 80)
+	for _, d := range s.FindNearDuplicates(pageReports) {
+		delete(planted, d.PageReportId)
+	}
+
+	if len(planted) > 0 {
+		t.Errorf("want all the planted near-duplicates found, %d missing", len(planted))
+	}
+}
+
+// Returns an HTML page with a text of n pseudo-random words generated from the seed.
+func nearDuplicateTestPage(seed uint32, n int, nav string) []byte {
+	words := []string{}
+	for range n {
+		seed = seed*1664525 + 1013904223
+		words = append(words, fmt.Sprintf("word%d", seed>>24))
+	}
+
+	return []byte(fmt.Sprintf(`<html><body><nav>%s</nav><main><p>%s</p></main></body></html>`, nav, strings.Join(words, " ")))
+}
+
+// Test the fingerprint of the pages' main text. Pages with a few different words or a different
+// navigation must be similar, while pages with a different text must not.
+func TestSimHash(t *testing.T) {
+	u, err := url.Parse("https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := &http.Header{"Content-Type": []string{"text/html"}}
+	fingerprint := func(body []byte) uint64 {
+		pageReport, _, err := services.NewHTMLParser(u, 200, header, body, int64(len(body)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return pageReport.SimHash
+	}
+
+	page := nearDuplicateTestPage(1, 300, "Home About Contact")
+	similar := []byte(strings.Replace(string(page), "Home About Contact", "Home Blog Shop Contact", 1))
+	similar = []byte(strings.Replace(string(similar), "word", "other", 2))
+	different := nearDuplicateTestPage(2, 300, "Home About Contact")
+	short := nearDuplicateTestPage(1, 20, "Home About Contact")
+
+	a := fingerprint(page)
+	if a == 0 {
+		t.Fatal("page fingerprint should not be zero")
+	}
+
+	if s := services.SimHashSimilarity(a, fingerprint(similar)); s < 95 {
+		t.Errorf("similar pages: want at least 95%% similarity got %d%%", s)
+	}
+
+	if s := services.SimHashSimilarity(a, fingerprint(different)); s >= 80 {
+		t.Errorf("different pages: want less than 80%% similarity got %d%%", s)
+	}
+
+	if fingerprint(short) != 0 {
+		t.Error("short page fingerprint should be zero")
+	}
+}
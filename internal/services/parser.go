@@ -2,9 +2,11 @@ package services
 
 import (
 	"bytes"
+	"encoding/json"
 	"log"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -498,6 +500,47 @@ func (p *Parser) htmlBodyNode() *html.Node {
 	return body
 }
 
+// Returns the text of the page's main content. It uses the main element, or the article
+// element if there's only one, falling back to the body. The navigation, header, footer
+// and aside elements are skipped as they are usually shared by all the pages of the site.
+func (p *Parser) htmlMainText() string {
+	root := htmlquery.FindOne(p.doc, "//main")
+	if root == nil {
+		if articles := htmlquery.Find(p.doc, "//article"); len(articles) == 1 {
+			root = articles[0]
+		} else {
+			root = p.htmlBodyNode()
+		}
+	}
+
+	if root == nil {
+		return ""
+	}
+
+	skip := []string{"script", "style", "noscript", "template", "svg", "nav", "header", "footer", "aside", "form"}
+
+	var b strings.Builder
+	var output func(*html.Node)
+	output = func(n *html.Node) {
+		if n.Type == html.ElementNode && slices.Contains(skip, n.Data) {
+			return
+		}
+
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteString(" ")
+		}
+
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			output(c)
+		}
+	}
+
+	output(root)
+
+	return b.String()
+}
+
 // Parse hreflang links from the HTTP header
 func (p *Parser) headersCanonical() string {
 	linkHeaderElements := strings.Split(p.Headers.Get("Link"), ",")
@@ -569,6 +612,42 @@ func (p *Parser) headersRobots() string {
 	return p.Headers.Get("X-Robots-Tag")
 }
 
+// Returns the cookies set in the Set-Cookie headers. Cookies without the Domain attribute
+// are only sent to the page's host, so it is used as their domain.
+func (p *Parser) headersCookies() []models.Cookie {
+	cookies := []models.Cookie{}
+	for _, line := range p.Headers.Values("Set-Cookie") {
+		c, err := http.ParseSetCookie(line)
+		if err != nil {
+			continue
+		}
+
+		cookie := models.Cookie{
+			Name:     c.Name,
+			Domain:   strings.TrimPrefix(c.Domain, "."),
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+
+		if cookie.Domain == "" {
+			cookie.Domain = p.ParsedURL.Hostname()
+		}
+
+		switch c.SameSite {
+		case http.SameSiteLaxMode:
+			cookie.SameSite = "Lax"
+		case http.SameSiteStrictMode:
+			cookie.SameSite = "Strict"
+		case http.SameSiteNoneMode:
+			cookie.SameSite = "None"
+		}
+
+		cookies = append(cookies, cookie)
+	}
+
+	return cookies
+}
+
 // Return the contents of the HTTP Location header.
 func (p *Parser) headersLocation() string {
 	l, err := urlutils.AbsoluteURL(p.Headers.Get("Location"), p.doc, p.ParsedURL)
@@ -621,6 +700,249 @@ func (p *Parser) parseSrcSet(srcset string) []string {
 	return imageURLs
 }
 
+// Extract the Open Graph and Twitter Card meta tags. The image tags also get their absolute URL
+// if it is valid, so it can be crawled.
+// ex. <meta property="og:title" content="Title">
+func (p *Parser) htmlSocialTags() []models.SocialTag {
+	tags := []models.SocialTag{}
+	metas := htmlquery.Find(p.doc, "//meta[@content]")
+	for _, n := range metas {
+		property := strings.ToLower(strings.TrimSpace(htmlquery.SelectAttr(n, "property")))
+		if property == "" {
+			property = strings.ToLower(strings.TrimSpace(htmlquery.SelectAttr(n, "name")))
+		}
+
+		if !strings.HasPrefix(property, "og:") && !strings.HasPrefix(property, "twitter:") {
+			continue
+		}
+
+		tag := models.SocialTag{
+			Property: property,
+			Content:  strings.TrimSpace(htmlquery.SelectAttr(n, "content")),
+		}
+
+		switch property {
+		case "og:image", "og:image:url", "og:image:secure_url", "twitter:image":
+			if u, err := urlutils.AbsoluteURL(tag.Content, p.doc, p.ParsedURL); err == nil && tag.Content != "" {
+				tag.URL = u.String()
+			}
+		}
+
+		tags = append(tags, tag)
+	}
+
+	return tags
+}
+
+// Extract the structured data items in JSON-LD scripts and microdata attributes.
+func (p *Parser) htmlStructuredData() []models.StructuredData {
+	return append(p.htmlJSONLD(), p.htmlMicrodata()...)
+}
+
+// Extract the JSON-LD items. Scripts with a list of items or a @graph are split so every item
+// is returned on its own. Scripts that can't be parsed are returned as a single item with the error.
+// ex. <script type="application/ld+json">{"@context": "https://schema.org", "@type": "Product"}</script>
+func (p *Parser) htmlJSONLD() []models.StructuredData {
+	items := []models.StructuredData{}
+	scripts := htmlquery.Find(p.doc, "//script[@type=\"application/ld+json\"]")
+	for _, n := range scripts {
+		raw := strings.TrimSpace(htmlquery.InnerText(n))
+
+		var v any
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			items = append(items, models.StructuredData{Format: models.StructuredDataJSONLD, Raw: raw, Error: err.Error()})
+			continue
+		}
+
+		objects := jsonLDObjects(v)
+		if len(objects) == 0 {
+			items = append(items, models.StructuredData{Format: models.StructuredDataJSONLD, Raw: raw, Error: "no JSON-LD objects found"})
+			continue
+		}
+
+		for _, o := range objects {
+			b, err := json.Marshal(o)
+			if err != nil {
+				continue
+			}
+
+			items = append(items, models.StructuredData{
+				Format: models.StructuredDataJSONLD,
+				Type:   schemaType(o["@type"]),
+				Raw:    string(b),
+			})
+		}
+	}
+
+	return items
+}
+
+// Extract the top level microdata items, converting their properties to JSON.
+// ex. <div itemscope itemtype="https://schema.org/Product"><span itemprop="name">Name</span></div>
+func (p *Parser) htmlMicrodata() []models.StructuredData {
+	items := []models.StructuredData{}
+	scopes := htmlquery.Find(p.doc, "//*[@itemscope and not(@itemprop)]")
+	for _, n := range scopes {
+		item := microdataItem(n)
+
+		b, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+
+		items = append(items, models.StructuredData{
+			Format: models.StructuredDataMicrodata,
+			Type:   schemaType(item["@type"]),
+			Raw:    string(b),
+		})
+	}
+
+	return items
+}
+
+// Returns the JSON-LD objects in a parsed JSON value, which can be an object, a list of
+// objects or an object with a @graph list.
+func jsonLDObjects(v any) []map[string]any {
+	switch t := v.(type) {
+	case map[string]any:
+		if graph, ok := t["@graph"].([]any); ok {
+			return jsonLDObjects(graph)
+		}
+
+		return []map[string]any{t}
+	case []any:
+		objects := []map[string]any{}
+		for _, i := range t {
+			objects = append(objects, jsonLDObjects(i)...)
+		}
+
+		return objects
+	}
+
+	return nil
+}
+
+// Returns the schema type names in a @type value separated by commas, removing the vocabulary
+// so both "Product" and "https://schema.org/Product" return "Product".
+func schemaType(v any) string {
+	var types []any
+	switch t := v.(type) {
+	case string:
+		types = []any{t}
+	case []any:
+		types = t
+	}
+
+	names := []string{}
+	for _, t := range types {
+		s, ok := t.(string)
+		if !ok {
+			continue
+		}
+
+		names = append(names, s[strings.LastIndexAny(s, "/:#")+1:])
+	}
+
+	return strings.Join(names, ",")
+}
+
+// Returns a microdata item as a JSON-LD like object with its type and properties.
+func microdataItem(n *html.Node) map[string]any {
+	item := map[string]any{}
+
+	types := []any{}
+	for _, t := range strings.Fields(htmlquery.SelectAttr(n, "itemtype")) {
+		types = append(types, t)
+	}
+
+	switch len(types) {
+	case 0:
+	case 1:
+		item["@type"] = types[0]
+	default:
+		item["@type"] = types
+	}
+
+	microdataProperties(n, item)
+
+	return item
+}
+
+// Adds the properties found in the node's descendants to the item. Nested items are added
+// as objects and their properties are not added to the parent item.
+func microdataProperties(n *html.Node, item map[string]any) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+
+		scope := hasAttr(c, "itemscope")
+		names := strings.Fields(htmlquery.SelectAttr(c, "itemprop"))
+		if len(names) > 0 {
+			var value any
+			if scope {
+				value = microdataItem(c)
+			} else {
+				value = microdataValue(c)
+			}
+
+			for _, name := range names {
+				if current, ok := item[name]; ok {
+					if values, ok := current.([]any); ok {
+						item[name] = append(values, value)
+					} else {
+						item[name] = []any{current, value}
+					}
+				} else {
+					item[name] = value
+				}
+			}
+		}
+
+		if !scope {
+			microdataProperties(c, item)
+		}
+	}
+}
+
+// Returns the value of a microdata property depending on the element it is set in.
+func microdataValue(n *html.Node) string {
+	attr := ""
+	switch n.Data {
+	case "meta":
+		attr = "content"
+	case "a", "area", "link":
+		attr = "href"
+	case "audio", "embed", "iframe", "img", "source", "track", "video":
+		attr = "src"
+	case "object":
+		attr = "data"
+	case "data", "meter":
+		attr = "value"
+	case "time":
+		if hasAttr(n, "datetime") {
+			attr = "datetime"
+		}
+	}
+
+	if attr != "" {
+		return strings.TrimSpace(htmlquery.SelectAttr(n, attr))
+	}
+
+	return strings.Join(strings.Fields(htmlquery.InnerText(n)), " ")
+}
+
+// Returns true if the node has the attribute, even if its value is empty.
+func hasAttr(n *html.Node, name string) bool {
+	for _, a := range n.Attr {
+		if a.Key == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Build a new link from a node element
 func (p *Parser) newLink(n *html.Node) (models.Link, error) {
 	href := htmlquery.SelectAttr(n, "href")
@@ -2,10 +2,15 @@ package services
 
 import (
 	"errors"
+	"math"
 	"net/url"
 	"strings"
+	"time"
 
+	"github.com/stjudewashere/seonaut/internal/config"
 	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/schedule"
+	"github.com/stjudewashere/seonaut/internal/urlutils"
 )
 
 type (
@@ -26,6 +31,7 @@ type (
 	ProjectService struct {
 		repository     ProjectServiceRepository
 		archiveRemover ArchiveRemover
+		config         *config.CrawlerConfig
 	}
 )
 
@@ -35,15 +41,124 @@ var (
 
 	// Error returned when the project's user agent is empty.
 	ErrUserAgent = errors.New("user agent string must not be empty")
+
+	// Error returned when the project's crawl retention is out of range.
+	ErrCrawlRetention = errors.New("crawl retention out of range")
+
+	// Error returned when the project's crawl schedule is not a valid cron expression.
+	ErrCrawlSchedule = errors.New("crawl schedule not valid")
+
+	// Error returned when the project has a crawl schedule and uses basic auth or a form
+	// login, which can't be crawled automatically as their passwords are not stored.
+	ErrCrawlScheduleAuth = errors.New("crawl schedule not allowed with authentication")
+
+	// Error returned when the project's number of crawl workers is out of range.
+	ErrCrawlWorkers = errors.New("crawl workers out of range")
+
+	// Error returned when the project's delay between requests is out of range.
+	ErrCrawlDelay = errors.New("crawl delay out of range")
+
+	// Error returned when the project's max requests per second is negative.
+	ErrCrawlRate = errors.New("crawl rate not valid")
+
+	// Error returned when the project's crawl timeout is out of range.
+	ErrCrawlTimeout = errors.New("crawl timeout out of range")
+
+	// Error returned when the project's crawl limit is out of range.
+	ErrCrawlLimit = errors.New("crawl limit out of range")
+
+	// Error returned when the project's max depth is out of range.
+	ErrCrawlDepth = errors.New("max depth out of range")
+
+	// Error returned when the project's include or exclude rules are not valid.
+	ErrCrawlScope = errors.New("crawl scope rules not valid")
+
+	// Error returned when the project's list of ignored query parameters is too long.
+	ErrIgnoredParams = errors.New("ignored parameters not valid")
+
+	// Error returned when the project's custom headers are not valid.
+	ErrCustomHeaders = errors.New("custom headers not valid")
+
+	// Error returned when the project's custom cookies are not valid.
+	ErrCustomCookies = errors.New("custom cookies not valid")
+
+	// Error returned when the project's form login settings are not valid.
+	ErrFormLogin = errors.New("form login not valid")
+
+	// Error returned when the project's proxy URL is not valid.
+	ErrProxy = errors.New("proxy not valid")
+
+	// Error returned when the project's request timeout is out of range.
+	ErrRequestTimeout = errors.New("request timeout out of range")
+
+	// Error returned when the project's number of request retries is out of range.
+	ErrRequestRetries = errors.New("request retries out of range")
+
+	// Error returned when the project's custom extraction rules are not valid.
+	ErrExtractors = errors.New("extractors not valid")
+
+	// Error returned when the project's custom search rules are not valid.
+	ErrCustomSearch = errors.New("custom search not valid")
 )
 
-func NewProjectService(r ProjectServiceRepository, a ArchiveRemover) *ProjectService {
+const (
+	MaxCrawlRetention     = 10    // Max number of crawls a project can keep.
+	MaxCrawlWorkers       = 20    // Max number of concurrent requests.
+	MaxCrawlDelay         = 60000 // Max delay between requests in milliseconds.
+	MaxCrawlTimeout       = 1440  // Max crawl timeout in minutes.
+	DefaultCrawlWorkers   = 2     // Default number of concurrent requests.
+	DefaultCrawlMinDelay  = 0     // Default min delay between requests in milliseconds.
+	DefaultCrawlMaxDelay  = 1500  // Default max delay between requests in milliseconds.
+	DefaultCrawlTimeout   = 120   // Default crawl timeout in minutes.
+	MaxScopeRulesLength   = 2048  // Max length of the include and exclude rules.
+	MaxIgnoredParams      = 1024  // Max length of the ignored query parameters list.
+	MaxCustomHeaders      = 2048  // Max length of the custom headers and cookies.
+	MaxLoginURL           = 2048  // Max length of the form login URL.
+	MaxLoginField         = 256   // Max length of the form login fields, credentials and markers.
+	MaxProxyURL           = 2048  // Max length of the proxy URL.
+	MaxRequestTimeout     = 60    // Max request timeout in seconds.
+	MaxRequestRetries     = 5     // Max number of retries of a request.
+	DefaultRequestRetries = 2     // Default number of retries of a request.
+	MaxExtractors         = 4096  // Max length of the custom extraction rules.
+	MaxCustomSearch       = 4096  // Max length of the custom search rules.
+)
+
+func NewProjectService(r ProjectServiceRepository, a ArchiveRemover, c *config.CrawlerConfig) *ProjectService {
 	return &ProjectService{
 		repository:     r,
 		archiveRemover: a,
+		config:         c,
 	}
 }
 
+// MaxCrawlLimit returns the admin-defined ceiling for the projects' crawl limit.
+// It returns zero if there is no ceiling.
+func (s *ProjectService) MaxCrawlLimit() int {
+	return s.config.MaxCrawlLimit
+}
+
+// DefaultCrawlLimit returns the crawl limit for projects that don't set their own,
+// making sure it is not over the admin-defined ceiling.
+func (s *ProjectService) DefaultCrawlLimit() int {
+	if s.config.MaxCrawlLimit > 0 {
+		return min(CrawlLimit, s.config.MaxCrawlLimit)
+	}
+
+	return CrawlLimit
+}
+
+// DefaultIgnoredParams returns the comma separated list of query parameters that are
+// removed from the URLs by default.
+func (s *ProjectService) DefaultIgnoredParams() string {
+	return strings.Join(urlutils.DefaultIgnoredParams, ",")
+}
+
+// MaxDepth returns the admin-defined ceiling for the projects' max depth.
+// It returns zero if there is no ceiling.
+func (s *ProjectService) MaxDepth() int {
+	return s.config.MaxDepth
+}
+
 // SaveProject stores a new project.
 // It trims the spaces in the project's URL field and checks the scheme to
 // make sure it is http or https.
@@ -118,8 +233,8 @@ func (s *ProjectService) DeleteAllUserProjects(user *models.User) {
 	}
 }
 
-// validateProject checks the project's URL and User-Agent to make sure they are valid.
-// It is called when a project is saved or updated.
+// validateProject checks the project's URL, User-Agent and crawl settings to make sure they are
+// valid. It is called when a project is saved or updated.
 func (s *ProjectService) validateProject(p *models.Project) error {
 	parsedURL, err := url.Parse(p.URL)
 	if err != nil {
@@ -135,5 +250,167 @@ func (s *ProjectService) validateProject(p *models.Project) error {
 		return ErrUserAgent
 	}
 
+	// Projects keep only the last crawl unless the retention is set.
+	if p.CrawlRetention == 0 {
+		p.CrawlRetention = 1
+	}
+
+	if p.CrawlRetention < 1 || p.CrawlRetention > MaxCrawlRetention {
+		return ErrCrawlRetention
+	}
+
+	if p.CrawlWorkers == 0 {
+		p.CrawlWorkers = DefaultCrawlWorkers
+	}
+
+	if p.CrawlWorkers < 1 || p.CrawlWorkers > MaxCrawlWorkers {
+		return ErrCrawlWorkers
+	}
+
+	if p.CrawlMinDelay < 0 || p.CrawlMaxDelay < p.CrawlMinDelay || p.CrawlMaxDelay > MaxCrawlDelay {
+		return ErrCrawlDelay
+	}
+
+	if p.CrawlMaxRPS < 0 || math.IsNaN(p.CrawlMaxRPS) || math.IsInf(p.CrawlMaxRPS, 0) {
+		return ErrCrawlRate
+	}
+
+	if p.CrawlTimeout == 0 {
+		p.CrawlTimeout = DefaultCrawlTimeout
+	}
+
+	if p.CrawlTimeout < 1 || p.CrawlTimeout > MaxCrawlTimeout {
+		return ErrCrawlTimeout
+	}
+
+	if p.RequestTimeout == 0 {
+		p.RequestTimeout = ClientTimeout
+	}
+
+	if p.RequestTimeout < 1 || p.RequestTimeout > MaxRequestTimeout {
+		return ErrRequestTimeout
+	}
+
+	if p.RequestRetries < 0 || p.RequestRetries > MaxRequestRetries {
+		return ErrRequestRetries
+	}
+
+	if p.CrawlLimit == 0 {
+		p.CrawlLimit = s.DefaultCrawlLimit()
+	}
+
+	if p.CrawlLimit < 1 || (s.config.MaxCrawlLimit > 0 && p.CrawlLimit > s.config.MaxCrawlLimit) {
+		return ErrCrawlLimit
+	}
+
+	// A zero max depth means there's no limit, which is not allowed
+	// if the admin has set a ceiling.
+	if p.MaxDepth == 0 {
+		p.MaxDepth = s.config.MaxDepth
+	}
+
+	if p.MaxDepth < 0 || (s.config.MaxDepth > 0 && p.MaxDepth > s.config.MaxDepth) {
+		return ErrCrawlDepth
+	}
+
+	p.IncludeRules = strings.TrimSpace(p.IncludeRules)
+	p.ExcludeRules = strings.TrimSpace(p.ExcludeRules)
+	if len(p.IncludeRules) > MaxScopeRulesLength || len(p.ExcludeRules) > MaxScopeRulesLength {
+		return ErrCrawlScope
+	}
+
+	if _, err := newCrawlScope(p); err != nil {
+		return ErrCrawlScope
+	}
+
+	params := []string{}
+	for _, param := range strings.Split(p.IgnoredParams, ",") {
+		if param = strings.TrimSpace(param); param != "" {
+			params = append(params, param)
+		}
+	}
+
+	p.IgnoredParams = strings.Join(params, ",")
+	if len(p.IgnoredParams) > MaxIgnoredParams {
+		return ErrIgnoredParams
+	}
+
+	p.CustomHeaders = strings.TrimSpace(p.CustomHeaders)
+	if _, err := ParseCustomHeaders(p.CustomHeaders); err != nil || len(p.CustomHeaders) > MaxCustomHeaders {
+		return ErrCustomHeaders
+	}
+
+	p.CustomCookies = strings.TrimSpace(p.CustomCookies)
+	if _, err := ParseCustomCookies(p.CustomCookies); err != nil || len(p.CustomCookies) > MaxCustomHeaders {
+		return ErrCustomCookies
+	}
+
+	p.Extractors = strings.TrimSpace(p.Extractors)
+	if _, err := ParseExtractors(p.Extractors); err != nil || len(p.Extractors) > MaxExtractors {
+		return ErrExtractors
+	}
+
+	p.CustomSearch = strings.TrimSpace(p.CustomSearch)
+	if _, err := ParseSearchRules(p.CustomSearch); err != nil || len(p.CustomSearch) > MaxCustomSearch {
+		return ErrCustomSearch
+	}
+
+	if err := validateFormLogin(p); err != nil {
+		return err
+	}
+
+	p.Proxy = strings.TrimSpace(p.Proxy)
+	if _, err := ParseProxy(p.Proxy); err != nil || len(p.Proxy) > MaxProxyURL {
+		return ErrProxy
+	}
+
+	// The next crawl is calculated from the schedule so changes in the
+	// schedule take effect immediately.
+	p.CrawlSchedule = strings.TrimSpace(p.CrawlSchedule)
+	p.NextCrawl = time.Time{}
+	if p.CrawlSchedule != "" {
+		sched, err := schedule.Parse(p.CrawlSchedule)
+		if err != nil {
+			return ErrCrawlSchedule
+		}
+
+		if p.BasicAuth || p.LoginURL != "" {
+			return ErrCrawlScheduleAuth
+		}
+
+		p.NextCrawl = sched.Next(time.Now())
+	}
+
+	return nil
+}
+
+// validateFormLogin checks the form login settings. The login URL must be an absolute http or
+// https URL and the form field names are required if it is set. Otherwise the settings
+// are cleared so they are not kept once the form login is disabled.
+func validateFormLogin(p *models.Project) error {
+	p.LoginURL = strings.TrimSpace(p.LoginURL)
+	if p.LoginURL == "" {
+		p.LoginUserField, p.LoginPassField, p.LoginUser = "", "", ""
+		p.LoginSuccess, p.LoginLoggedOut = "", ""
+		return nil
+	}
+
+	p.LoginUserField = strings.TrimSpace(p.LoginUserField)
+	p.LoginPassField = strings.TrimSpace(p.LoginPassField)
+	if p.LoginUserField == "" || p.LoginPassField == "" || len(p.LoginURL) > MaxLoginURL {
+		return ErrFormLogin
+	}
+
+	for _, f := range []string{p.LoginUserField, p.LoginPassField, p.LoginUser, p.LoginSuccess, p.LoginLoggedOut} {
+		if len(f) > MaxLoginField {
+			return ErrFormLogin
+		}
+	}
+
+	u, err := url.Parse(p.LoginURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrFormLogin
+	}
+
 	return nil
 }
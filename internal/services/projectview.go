@@ -12,6 +12,7 @@ type (
 		FindProjectById(id int, uid int) (models.Project, error)
 
 		GetLastCrawl(*models.Project) models.Crawl
+		FindCrawlById(*models.Project, int64) (models.Crawl, error)
 	}
 
 	ProjectViewService struct {
@@ -48,6 +49,25 @@ func (s *ProjectViewService) GetProjectView(id, uid int) (*models.ProjectView, e
 	return v, nil
 }
 
+// GetProjectCrawlView returns a new ProjectView with the specified project and crawl.
+// If the crawl id is 0 the project's last crawl is used. It returns an error if the
+// crawl does not belong to the project or its data is no longer available.
+func (s *ProjectViewService) GetProjectCrawlView(id, uid int, cid int64) (*models.ProjectView, error) {
+	v, err := s.GetProjectView(id, uid)
+	if err != nil || cid == 0 || v.Crawl.Id == cid {
+		return v, err
+	}
+
+	c, err := s.repository.FindCrawlById(&v.Project, cid)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Crawl = c
+
+	return v, nil
+}
+
 // GetProjectViews returns a slice of ProjectViews with all of the user's
 // projects and its last crawls.
 func (s *ProjectViewService) GetProjectViews(uid int) []models.ProjectView {
@@ -12,6 +12,7 @@ const (
 	test_uid          = 1
 	test_pid          = 1
 	test_cid          = 1
+	test_previous_cid = 2
 	test_url          = "https://example.org"
 	test_total_models = 2
 )
@@ -49,6 +50,14 @@ func (s *projectViewTestRepository) GetLastCrawl(p *models.Project) models.Crawl
 	return models.Crawl{}
 }
 
+func (s *projectViewTestRepository) FindCrawlById(p *models.Project, cid int64) (models.Crawl, error) {
+	if p.Id == test_pid && cid == test_previous_cid {
+		return models.Crawl{Id: test_previous_cid}, nil
+	}
+
+	return models.Crawl{}, errors.New("Test error")
+}
+
 var projectviewService = services.NewProjectViewService(&projectViewTestRepository{})
 
 // TestGetProjectView tests the GetProjectView function of the projectview service.
@@ -79,6 +88,34 @@ func TestGetProjectView_ProjectViewDoesNotExists(t *testing.T) {
 	}
 }
 
+// TestGetProjectCrawlView tests the GetProjectCrawlView function of the projectview service.
+// It verifies the crawl is loaded by id, and the last crawl is used if no id is specified.
+func TestGetProjectCrawlView(t *testing.T) {
+	table := []struct {
+		name      string
+		cid       int64
+		wantCid   int64
+		wantError bool
+	}{
+		{name: "Last crawl", cid: 0, wantCid: test_cid},
+		{name: "Crawl by id", cid: test_previous_cid, wantCid: test_previous_cid},
+		{name: "Non-existing crawl", cid: 9999, wantError: true},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			pv, err := projectviewService.GetProjectCrawlView(test_pid, test_uid, tt.cid)
+			if (err != nil) != tt.wantError {
+				t.Fatalf("GetProjectCrawlView() want error %v got %v", tt.wantError, err)
+			}
+
+			if !tt.wantError && pv.Crawl.Id != tt.wantCid {
+				t.Errorf("Crawl Id %d != %d", pv.Crawl.Id, tt.wantCid)
+			}
+		})
+	}
+}
+
 // TestGetProjectViews tests the GetProjectViews function of the projectview service.
 // It verifies the behavior of the GetProjectViews with existing projectviews.
 func TestGetProjectViews_ProjectViewsExist(t *testing.T) {
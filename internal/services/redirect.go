@@ -0,0 +1,109 @@
+package services
+
+import (
+	"log"
+	"net/url"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+)
+
+// Max number of redirects followed in a chain. Longer chains are cut at this length.
+const MaxRedirectHops = 20
+
+type (
+	RedirectServiceRepository interface {
+		FindRedirectPageReports(cid int64) []models.PageReport
+		SaveRedirectChains(cid int64, chains []models.RedirectChain) error
+	}
+
+	RedirectService struct {
+		repository RedirectServiceRepository
+	}
+)
+
+func NewRedirectService(r RedirectServiceRepository) *RedirectService {
+	return &RedirectService{repository: r}
+}
+
+// SaveRedirectChains follows the redirects of the crawl's page reports and stores the full
+// chain of every page report that redirects, both with HTTP redirects and meta refresh.
+func (s *RedirectService) SaveRedirectChains(crawl *models.Crawl) {
+	chains := s.BuildRedirectChains(s.repository.FindRedirectPageReports(crawl.Id))
+	if err := s.repository.SaveRedirectChains(crawl.Id, chains); err != nil {
+		log.Printf("SaveRedirectChains: cid %d %v", crawl.Id, err)
+	}
+}
+
+// BuildRedirectChains returns the redirect chains starting in each of the page reports that
+// redirect. The chain is followed until it reaches a URL that doesn't redirect, a URL that
+// wasn't crawled, a URL that was already visited in the chain or the max number of hops.
+func (s *RedirectService) BuildRedirectChains(pageReports []models.PageReport) []models.RedirectChain {
+	byURL := make(map[string]*models.PageReport, len(pageReports))
+	for i := range pageReports {
+		byURL[pageReports[i].URL] = &pageReports[i]
+	}
+
+	chains := []models.RedirectChain{}
+	for i := range pageReports {
+		start := &pageReports[i]
+		if start.RedirectURL == "" {
+			continue
+		}
+
+		chain := models.RedirectChain{PageReportId: start.Id}
+		visited := map[string]bool{}
+		var previous *url.URL
+
+		for p := start; ; {
+			hop, u := redirectHop(p, previous)
+			chain.Hops = append(chain.Hops, hop)
+			visited[p.URL] = true
+			previous = u
+
+			if p.RedirectURL == "" || len(chain.Hops) >= MaxRedirectHops {
+				break
+			}
+
+			next, ok := byURL[p.RedirectURL]
+			if !ok {
+				hop, _ := redirectHop(&models.PageReport{URL: p.RedirectURL}, previous)
+				chain.Hops = append(chain.Hops, hop)
+				break
+			}
+
+			if visited[next.URL] {
+				hop, _ := redirectHop(next, previous)
+				chain.Hops = append(chain.Hops, hop)
+				break
+			}
+
+			p = next
+		}
+
+		chains = append(chains, chain)
+	}
+
+	return chains
+}
+
+// redirectHop returns the hop for the page report and its parsed URL. The hop crosses scheme
+// or host if they are different from the previous hop's URL.
+func redirectHop(p *models.PageReport, previous *url.URL) (models.RedirectHop, *url.URL) {
+	hop := models.RedirectHop{
+		URL:        p.URL,
+		StatusCode: p.StatusCode,
+		Refresh:    p.Refresh != "" && p.RedirectURL != "" && (p.StatusCode < 300 || p.StatusCode >= 400),
+	}
+
+	u, err := url.Parse(p.URL)
+	if err != nil {
+		return hop, nil
+	}
+
+	if previous != nil {
+		hop.CrossScheme = u.Scheme != previous.Scheme
+		hop.CrossHost = u.Hostname() != previous.Hostname()
+	}
+
+	return hop, u
+}
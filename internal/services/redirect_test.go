@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// Create a mock repository for the redirect service.
+type redirectTestRepository struct {
+	pageReports []models.PageReport
+	saved       []models.RedirectChain
+}
+
+func (r *redirectTestRepository) FindRedirectPageReports(cid int64) []models.PageReport {
+	return r.pageReports
+}
+
+func (r *redirectTestRepository) SaveRedirectChains(cid int64, chains []models.RedirectChain) error {
+	r.saved = chains
+	return nil
+}
+
+// Test the redirect chains with every hop's status and whether it changes scheme or host.
+func TestBuildRedirectChains(t *testing.T) {
+	repository := &redirectTestRepository{
+		pageReports: []models.PageReport{
+			{Id: 1, URL: "http://example.com/a", StatusCode: 301, RedirectURL: "https://example.com/a"},
+			{Id: 2, URL: "https://example.com/a", StatusCode: 302, RedirectURL: "https://www.example.com/b"},
+			{Id: 3, URL: "https://www.example.com/b", StatusCode: 200, RedirectURL: "https://www.example.com/c", Refresh: "0; url=/c"},
+			{Id: 4, URL: "https://www.example.com/c", StatusCode: 404},
+			{Id: 5, URL: "https://example.com/loop-a", StatusCode: 308, RedirectURL: "https://example.com/loop-b"},
+			{Id: 6, URL: "https://example.com/loop-b", StatusCode: 307, RedirectURL: "https://example.com/loop-a"},
+			{Id: 7, URL: "https://example.com/d", StatusCode: 301, RedirectURL: "https://example.com/not-crawled"},
+		},
+	}
+
+	s := services.NewRedirectService(repository)
+	s.SaveRedirectChains(&models.Crawl{Id: 1})
+
+	chains := map[int64]models.RedirectChain{}
+	for _, c := range repository.saved {
+		chains[c.PageReportId] = c
+	}
+
+	if len(chains) != 6 {
+		t.Fatalf("want 6 chains got %d", len(chains))
+	}
+
+	want := []models.RedirectHop{
+		{URL: "http://example.com/a", StatusCode: 301},
+		{URL: "https://example.com/a", StatusCode: 302, CrossScheme: true},
+		{URL: "https://www.example.com/b", StatusCode: 200, Refresh: true, CrossHost: true},
+		{URL: "https://www.example.com/c", StatusCode: 404},
+	}
+
+	c := chains[1]
+	if len(c.Hops) != len(want) {
+		t.Fatalf("want %d hops got %d", len(want), len(c.Hops))
+	}
+
+	for i, h := range c.Hops {
+		if h != want[i] {
+			t.Errorf("hop %d: want %+v got %+v", i, want[i], h)
+		}
+	}
+
+	if c.Loop() {
+		t.Error("chain 1 is not a loop")
+	}
+
+	if c := chains[5]; !c.Loop() || len(c.Hops) != 3 {
+		t.Errorf("chain 5 should be a loop with 3 hops got %+v", c.Hops)
+	}
+
+	if d := chains[7].Destination(); d.URL != "https://example.com/not-crawled" || d.StatusCode != 0 {
+		t.Errorf("chain 7 destination: got %+v", d)
+	}
+}
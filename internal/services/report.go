@@ -2,6 +2,7 @@ package services
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/stjudewashere/seonaut/internal/models"
 )
@@ -26,6 +27,13 @@ type (
 		FindPageReportIframes(pageReport *models.PageReport, cid int64) []string
 		FindPageReportImages(pageReport *models.PageReport, cid int64) []models.Image
 		FindPageReportHreflangs(pageReport *models.PageReport, cid int64) []models.Hreflang
+		FindRedirectChain(pageReport *models.PageReport, cid int64) models.RedirectChain
+		FindNearDuplicate(pageReport *models.PageReport, cid int64) models.NearDuplicate
+		FindCertificate(host string, cid int64) (*models.Certificate, error)
+		FindPageReportStructuredData(pageReport *models.PageReport, cid int64) []models.StructuredData
+		FindPageReportSocialTags(pageReport *models.PageReport, cid int64) []models.SocialTag
+		FindPageReportExtractions(pageReport *models.PageReport, cid int64) []models.Extraction
+		FindExtractionNames(cid int64) []string
 
 		GetNumberOfPagesForPageReport(cid int64, term string) int
 		GetNumberOfPagesForInlinks(*models.PageReport, int64) int
@@ -54,6 +62,30 @@ func (s *ReportService) GetPageReport(rid int, crawlId int64, tab string, page i
 	v.PageReport.Hreflangs = s.repository.FindPageReportHreflangs(&v.PageReport, crawlId)
 
 	switch tab {
+	case "details":
+		v.PageReport.StructuredData = s.repository.FindPageReportStructuredData(&v.PageReport, crawlId)
+
+		if v.PageReport.ParsedURL != nil && v.PageReport.ParsedURL.Scheme == "https" {
+			v.Certificate, _ = s.repository.FindCertificate(v.PageReport.ParsedURL.Hostname(), crawlId)
+		}
+
+		if slices.Contains(v.ErrorTypes, "ERROR_ACTIVE_MIXED_CONTENT") {
+			v.PageReport.Scripts = s.repository.FindPageReportScripts(&v.PageReport, crawlId)
+			v.PageReport.Styles = s.repository.FindPageReportStyles(&v.PageReport, crawlId)
+			v.PageReport.Iframes = s.repository.FindPageReportIframes(&v.PageReport, crawlId)
+			v.ActiveMixedContent = v.PageReport.ActiveMixedContent()
+		}
+
+		if slices.Contains(v.ErrorTypes, "ERROR_PASSIVE_MIXED_CONTENT") {
+			v.PageReport.Images = s.repository.FindPageReportImages(&v.PageReport, crawlId)
+			v.PageReport.Audios = s.repository.FindPageReportAudios(&v.PageReport, crawlId)
+			v.PageReport.Videos = s.repository.FindPageReportVideos(&v.PageReport, crawlId)
+			v.PassiveMixedContent = v.PageReport.PassiveMixedContent()
+		}
+
+		if slices.Contains(v.ErrorTypes, "ERROR_NEAR_DUPLICATE_CONTENT") {
+			v.NearDuplicate = s.repository.FindNearDuplicate(&v.PageReport, crawlId)
+		}
 	case "internal":
 		v.PageReport.InternalLinks = s.repository.FindLinks(&v.PageReport, crawlId, page)
 	case "external":
@@ -62,6 +94,8 @@ func (s *ReportService) GetPageReport(rid int, crawlId int64, tab string, page i
 		v.InLinks = s.repository.FindInLinks(v.PageReport.URL, crawlId, page)
 	case "redirections":
 		v.Redirects = s.repository.FindPageReportsRedirectingToURL(v.PageReport.URL, crawlId, page)
+	case "chain":
+		v.RedirectChain = s.repository.FindRedirectChain(&v.PageReport, crawlId)
 	case "styles":
 		v.PageReport.Styles = s.repository.FindPageReportStyles(&v.PageReport, crawlId)
 	case "scripts":
@@ -74,6 +108,9 @@ func (s *ReportService) GetPageReport(rid int, crawlId int64, tab string, page i
 		v.PageReport.Iframes = s.repository.FindPageReportIframes(&v.PageReport, crawlId)
 	case "images":
 		v.PageReport.Images = s.repository.FindPageReportImages(&v.PageReport, crawlId)
+	case "social":
+		v.PageReport.SocialTags = s.repository.FindPageReportSocialTags(&v.PageReport, crawlId)
+		v.SocialPreview = models.NewSocialPreview(&v.PageReport)
 	}
 
 	v.Paginator = s.getPaginator(&v.PageReport, crawlId, tab, page)
@@ -147,6 +184,44 @@ func (s *ReportService) GetPaginatedReports(crawlId int64, currentPage int, term
 	return paginatorView, nil
 }
 
+// Returns the names of the custom extraction rules with values in the crawl.
+func (s *ReportService) GetExtractionNames(crawlId int64) []string {
+	return s.repository.FindExtractionNames(crawlId)
+}
+
+// Returns the values extracted from each of the PageReports with the named extraction rules,
+// mapped by PageReport Id.
+func (s *ReportService) GetExtractedValues(crawlId int64, pageReports []models.PageReport, names []string) map[int64][]string {
+	values := make(map[int64][]string, len(pageReports))
+	if len(names) == 0 {
+		return values
+	}
+
+	for _, p := range pageReports {
+		p.Extractions = s.repository.FindPageReportExtractions(&p, crawlId)
+		values[p.Id] = p.ExtractedValues(names)
+	}
+
+	return values
+}
+
+// Returns a channel with the PageReports received in the prStream channel after loading
+// the values extracted with the custom extraction rules.
+func (s *ReportService) WithExtractions(crawlId int64, prStream <-chan *models.PageReport) <-chan *models.PageReport {
+	stream := make(chan *models.PageReport)
+
+	go func() {
+		defer close(stream)
+
+		for p := range prStream {
+			p.Extractions = s.repository.FindPageReportExtractions(p, crawlId)
+			stream <- p
+		}
+	}()
+
+	return stream
+}
+
 // Returns a channel of crawlable PageReports that can be included in a sitemap.
 func (s *ReportService) GetSitemapPageReports(crawlId int64) <-chan *models.PageReport {
 	return s.repository.FindSitemapPageReports(crawlId)
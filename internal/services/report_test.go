@@ -150,6 +150,28 @@ func (s *reportTestRepository) FindPageReportImages(pageReport *models.PageRepor
 func (s *reportTestRepository) FindPageReportHreflangs(pageReport *models.PageReport, cid int64) []models.Hreflang {
 	return []models.Hreflang{}
 }
+func (s *reportTestRepository) FindCertificate(host string, cid int64) (*models.Certificate, error) {
+	return nil, nil
+}
+func (s *reportTestRepository) FindPageReportStructuredData(pageReport *models.PageReport, cid int64) []models.StructuredData {
+	return []models.StructuredData{}
+}
+func (s *reportTestRepository) FindPageReportSocialTags(pageReport *models.PageReport, cid int64) []models.SocialTag {
+	return []models.SocialTag{}
+}
+func (s *reportTestRepository) FindPageReportExtractions(pageReport *models.PageReport, cid int64) []models.Extraction {
+	return []models.Extraction{{Name: "price", Value: "10"}, {Name: "tag", Value: "a"}, {Name: "tag", Value: "b"}}
+}
+func (s *reportTestRepository) FindExtractionNames(cid int64) []string {
+	return []string{"price", "tag"}
+}
+func (s *reportTestRepository) FindRedirectChain(pageReport *models.PageReport, cid int64) models.RedirectChain {
+	return models.RedirectChain{PageReportId: pageReport.Id}
+}
+
+func (s *reportTestRepository) FindNearDuplicate(pageReport *models.PageReport, cid int64) models.NearDuplicate {
+	return models.NearDuplicate{PageReportId: pageReport.Id}
+}
 
 var reportservice = services.NewReportService(&reportTestRepository{})
 
@@ -215,3 +237,24 @@ func TestGetPageReport(t *testing.T) {
 		t.Errorf("v.Redirects: %d != 1", len(vr.Redirects))
 	}
 }
+
+func TestGetExtractedValues(t *testing.T) {
+	names := reportservice.GetExtractionNames(crawlId)
+	pageReports := []models.PageReport{{Id: reportId}}
+
+	values := reportservice.GetExtractedValues(crawlId, pageReports, append(names, "missing"))
+	expected := []string{"10", "a | b", ""}
+	if len(values[reportId]) != len(expected) {
+		t.Fatalf("GetExtractedValues: %d != %d", len(values[reportId]), len(expected))
+	}
+
+	for i, v := range expected {
+		if values[reportId][i] != v {
+			t.Errorf("GetExtractedValues %d: %q != %q", i, values[reportId][i], v)
+		}
+	}
+
+	if len(reportservice.GetExtractedValues(crawlId, pageReports, []string{})) != 0 {
+		t.Errorf("GetExtractedValues: values should be empty without extraction rules")
+	}
+}
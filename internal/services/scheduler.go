@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/schedule"
+)
+
+const SchedulerInterval = 60 // Seconds between checks for scheduled crawls.
+
+type (
+	SchedulerServiceRepository interface {
+		FindScheduledProjects(before time.Time) []models.Project
+		UpdateNextCrawl(p *models.Project) error
+	}
+
+	SchedulerCrawler interface {
+		StartCrawler(p models.Project, b models.BasicAuth) error
+	}
+
+	SchedulerService struct {
+		repository SchedulerServiceRepository
+		crawler    SchedulerCrawler
+	}
+)
+
+func NewSchedulerService(r SchedulerServiceRepository, c SchedulerCrawler) *SchedulerService {
+	return &SchedulerService{
+		repository: r,
+		crawler:    c,
+	}
+}
+
+// Start checks for scheduled crawls that are due every SchedulerInterval seconds
+// in a separate go routine.
+func (s *SchedulerService) Start() {
+	go func() {
+		ticker := time.NewTicker(SchedulerInterval * time.Second)
+		defer ticker.Stop()
+
+		for now := range ticker.C {
+			s.Run(now)
+		}
+	}()
+}
+
+// Run starts the crawlers of the projects with a scheduled crawl due at the specified time.
+// The project's next crawl is updated before the crawler starts, so a run that is skipped
+// because the project is already being crawled or because it needs basic auth credentials
+// is not retried until the next scheduled time.
+func (s *SchedulerService) Run(now time.Time) {
+	for _, p := range s.repository.FindScheduledProjects(now) {
+		sched, err := schedule.Parse(p.CrawlSchedule)
+		if err != nil {
+			log.Printf("Scheduler: project %d: %v", p.Id, err)
+			continue
+		}
+
+		p.NextCrawl = sched.Next(now)
+		if err := s.repository.UpdateNextCrawl(&p); err != nil {
+			log.Printf("Scheduler: project %d: %v", p.Id, err)
+			continue
+		}
+
+		if p.BasicAuth {
+			log.Printf("Scheduler: skipping %s, it requires basic auth credentials", p.URL)
+			continue
+		}
+
+		if p.LoginURL != "" {
+			log.Printf("Scheduler: skipping %s, it requires the form login password", p.URL)
+			continue
+		}
+
+		err = s.crawler.StartCrawler(p, models.BasicAuth{})
+		if errors.Is(err, ErrAlreadyCrawling) {
+			log.Printf("Scheduler: skipping %s, it is already being crawled", p.URL)
+			continue
+		}
+
+		if err != nil {
+			log.Printf("Scheduler: project %d: %v", p.Id, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+const (
+	test_scheduled_pid = 1
+	test_crawling_pid  = 2
+	test_basicauth_pid = 3
+	test_login_pid     = 4
+)
+
+// Create a mock repository for the scheduler service.
+type schedulerTestRepository struct {
+	updated map[int64]time.Time
+}
+
+func (r *schedulerTestRepository) FindScheduledProjects(before time.Time) []models.Project {
+	return []models.Project{
+		{Id: test_scheduled_pid, URL: test_url, CrawlSchedule: "@daily"},
+		{Id: test_crawling_pid, URL: test_url, CrawlSchedule: "@weekly"},
+		{Id: test_basicauth_pid, URL: test_url, CrawlSchedule: "@daily", BasicAuth: true},
+		{Id: test_login_pid, URL: test_url, CrawlSchedule: "@daily", LoginURL: test_url + "/login"},
+	}
+}
+
+func (r *schedulerTestRepository) UpdateNextCrawl(p *models.Project) error {
+	r.updated[p.Id] = p.NextCrawl
+	return nil
+}
+
+// Create a mock crawler that fails if the project is already being crawled.
+type schedulerTestCrawler struct {
+	started []int64
+}
+
+func (c *schedulerTestCrawler) StartCrawler(p models.Project, b models.BasicAuth) error {
+	if p.Id == test_crawling_pid {
+		return services.ErrAlreadyCrawling
+	}
+
+	c.started = append(c.started, p.Id)
+
+	return nil
+}
+
+// TestSchedulerRun tests that the due projects are crawled and their next crawl is updated.
+func TestSchedulerRun(t *testing.T) {
+	repository := &schedulerTestRepository{updated: make(map[int64]time.Time)}
+	crawler := &schedulerTestCrawler{}
+	scheduler := services.NewSchedulerService(repository, crawler)
+
+	// Wednesday.
+	now := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+	scheduler.Run(now)
+
+	if len(crawler.started) != 1 || crawler.started[0] != test_scheduled_pid {
+		t.Errorf("Started crawlers %v, expected [%d]", crawler.started, test_scheduled_pid)
+	}
+
+	expected := map[int64]time.Time{
+		test_scheduled_pid: time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC),
+		test_crawling_pid:  time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC),
+		test_basicauth_pid: time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC),
+		test_login_pid:     time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC),
+	}
+
+	for pid, next := range expected {
+		if !repository.updated[pid].Equal(next) {
+			t.Errorf("Project %d next crawl %v, expected %v", pid, repository.updated[pid], next)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+	"unicode"
+)
+
+const (
+	simHashShingle  = 3  // Number of words of the shingles used as the fingerprint features.
+	simHashMinWords = 50 // Pages with fewer words in their main text are not fingerprinted.
+)
+
+// Returns the 64 bit SimHash fingerprint of the text. The features are the overlapping
+// shingles of consecutive words, so similar texts have fingerprints that differ in only
+// a few bits. It returns zero if the text is too short to be compared with other pages.
+func simHash(text string) uint64 {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	if len(words) < simHashMinWords {
+		return 0
+	}
+
+	var v [64]int
+	h := fnv.New64a()
+	for i := 0; i+simHashShingle <= len(words); i++ {
+		h.Reset()
+		h.Write([]byte(strings.Join(words[i:i+simHashShingle], " ")))
+		sum := h.Sum64()
+		for b := range v {
+			if sum&(1<<b) != 0 {
+				v[b]++
+			} else {
+				v[b]--
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for b, c := range v {
+		if c > 0 {
+			fingerprint |= 1 << b
+		}
+	}
+
+	return fingerprint
+}
+
+// SimHashSimilarity returns the similarity percentage of two SimHash fingerprints,
+// which is the percentage of bits they have in common.
+func SimHashSimilarity(a, b uint64) int {
+	return 100 * (64 - bits.OnesCount64(a^b)) / 64
+}
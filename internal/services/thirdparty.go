@@ -0,0 +1,62 @@
+package services
+
+import (
+	"net/url"
+	"sort"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/urlutils"
+)
+
+// BuildThirdPartyInventory groups the resources loaded from a different site than the page
+// loading them by registrable domain and type. The domains are sorted by the number of pages
+// that load them, so the most widely used third-parties come first.
+func BuildThirdPartyInventory(resources <-chan *models.ExportResource) []models.ThirdPartyDomain {
+	type key struct{ domain, t string }
+
+	domains := map[key]*models.ThirdPartyDomain{}
+	pages := map[key]map[string]bool{}
+
+	for r := range resources {
+		pageURL, err := url.Parse(r.Origin)
+		if err != nil {
+			continue
+		}
+
+		resourceURL, err := url.Parse(r.URL)
+		if err != nil || !urlutils.ThirdParty(pageURL, resourceURL) {
+			continue
+		}
+
+		k := key{domain: urlutils.SiteDomain(resourceURL.Hostname()), t: r.Type}
+		d, ok := domains[k]
+		if !ok {
+			d = &models.ThirdPartyDomain{Domain: k.domain, Type: k.t}
+			domains[k] = d
+			pages[k] = map[string]bool{}
+		}
+
+		d.Resources = append(d.Resources, *r)
+		pages[k][r.Origin] = true
+	}
+
+	inventory := []models.ThirdPartyDomain{}
+	for k, d := range domains {
+		d.Pages = len(pages[k])
+		inventory = append(inventory, *d)
+	}
+
+	sort.Slice(inventory, func(i, j int) bool {
+		if inventory[i].Pages != inventory[j].Pages {
+			return inventory[i].Pages > inventory[j].Pages
+		}
+
+		if inventory[i].Domain != inventory[j].Domain {
+			return inventory[i].Domain < inventory[j].Domain
+		}
+
+		return inventory[i].Type < inventory[j].Type
+	})
+
+	return inventory
+}
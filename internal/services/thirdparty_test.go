@@ -0,0 +1,54 @@
+package services_test
+
+import (
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/models"
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// Test the third-party inventory groups the resources by domain and type, ignoring the
+// resources of the same site and counting the distinct pages that load each domain.
+func TestBuildThirdPartyInventory(t *testing.T) {
+	resources := []*models.ExportResource{
+		{Origin: "https://example.com/", Type: "script", URL: "https://example.com/app.js"},
+		{Origin: "https://example.com/", Type: "script", URL: "https://cdn.example.com/app.js"},
+		{Origin: "https://example.com/", Type: "script", URL: "https://www.googletagmanager.com/gtm.js"},
+		{Origin: "https://example.com/", Type: "script", URL: "https://cdn.jsdelivr.net/npm/a.js"},
+		{Origin: "https://example.com/", Type: "script", URL: "https://cdn.jsdelivr.net/npm/b.js"},
+		{Origin: "https://example.com/about", Type: "script", URL: "https://www.googletagmanager.com/gtm.js"},
+		{Origin: "https://example.com/about", Type: "style", URL: "https://fonts.googleapis.com/css"},
+	}
+
+	stream := make(chan *models.ExportResource)
+	go func() {
+		defer close(stream)
+		for _, r := range resources {
+			stream <- r
+		}
+	}()
+
+	inventory := services.BuildThirdPartyInventory(stream)
+
+	expected := []struct {
+		domain    string
+		t         string
+		pages     int
+		resources int
+	}{
+		{"googletagmanager.com", "script", 2, 2},
+		{"fonts.googleapis.com", "style", 1, 1},
+		{"jsdelivr.net", "script", 1, 2},
+	}
+
+	if len(inventory) != len(expected) {
+		t.Fatalf("inventory %d != %d", len(inventory), len(expected))
+	}
+
+	for i, e := range expected {
+		d := inventory[i]
+		if d.Domain != e.domain || d.Type != e.t || d.Pages != e.pages || len(d.Resources) != e.resources {
+			t.Errorf("inventory %d: %s %s %d %d", i, d.Domain, d.Type, d.Pages, len(d.Resources))
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// ParseURLList reads a list of URLs from a text or CSV file. Each line is split into fields
+// by commas, semicolons or tabs and the first field that is an absolute http or https URL
+// is added to the list, so header rows and other columns are ignored. Duplicated URLs are
+// removed and at most limit URLs are returned.
+func ParseURLList(r io.Reader, limit int) ([]*url.URL, error) {
+	urls := []*url.URL{}
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() && len(urls) < limit {
+		line := strings.TrimPrefix(scanner.Text(), "\ufeff")
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ';' || r == '\t'
+		})
+
+		for _, f := range fields {
+			f = strings.Trim(strings.TrimSpace(f), `"'`)
+			u, err := url.Parse(f)
+			if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				continue
+			}
+
+			u.Fragment = ""
+			if u.Path == "" {
+				u.Path = "/"
+			}
+
+			if !seen[u.String()] {
+				seen[u.String()] = true
+				urls = append(urls, u)
+			}
+
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(urls) == 0 {
+		return nil, ErrEmptyURLList
+	}
+
+	return urls, nil
+}
@@ -0,0 +1,65 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/services"
+)
+
+// Test ParseURLList with text and CSV lists.
+func TestParseURLList(t *testing.T) {
+	table := []struct {
+		name  string
+		list  string
+		limit int
+		want  []string
+	}{
+		{
+			name:  "Text list",
+			list:  "https://example.com\nhttps://example.com/page#top\n\nnot a url\nftp://example.com/file\n",
+			limit: 10,
+			want:  []string{"https://example.com/", "https://example.com/page"},
+		},
+		{
+			name:  "CSV list with header",
+			list:  "\ufeffsource,target\r\n\"https://example.com/old\",\"https://example.com/new\"\r\nhttps://example.com/old,https://example.com/other\r\n",
+			limit: 10,
+			want:  []string{"https://example.com/old"},
+		},
+		{
+			name:  "Limit",
+			list:  "https://example.com/1;x\nhttps://example.com/2\thttps://example.com/3\nhttps://example.com/4",
+			limit: 2,
+			want:  []string{"https://example.com/1", "https://example.com/2"},
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			urls, err := services.ParseURLList(strings.NewReader(tt.list), tt.limit)
+			if err != nil {
+				t.Fatalf("ParseURLList error: %v", err)
+			}
+
+			if len(urls) != len(tt.want) {
+				t.Fatalf("ParseURLList: want %d URLs got %d", len(tt.want), len(urls))
+			}
+
+			for i, u := range urls {
+				if u.String() != tt.want[i] {
+					t.Errorf("ParseURLList: want %s got %s", tt.want[i], u)
+				}
+			}
+		})
+	}
+}
+
+// Test ParseURLList returns an error if the list doesn't contain any valid URL.
+func TestParseURLListEmpty(t *testing.T) {
+	_, err := services.ParseURLList(strings.NewReader("url\nexample.com\n"), 10)
+	if !errors.Is(err, services.ErrEmptyURLList) {
+		t.Errorf("ParseURLList: want ErrEmptyURLList got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package urlutils
+
+import (
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultIgnoredParams are the query parameters removed by default: the utm_* tracking
+// parameters, the common click identifiers and the common session ids.
+var DefaultIgnoredParams = []string{
+	"utm_*",
+	"gclid",
+	"fbclid",
+	"msclkid",
+	"phpsessid",
+	"jsessionid",
+	"aspsessionid*",
+	"sessionid",
+	"sid",
+}
+
+// Default documents removed from the URL's path if the RemoveIndex option is set.
+var indexDocuments = map[string]bool{
+	"index.html":   true,
+	"index.htm":    true,
+	"index.php":    true,
+	"default.asp":  true,
+	"default.aspx": true,
+}
+
+// Normalizer normalizes URLs so different variations of the same URL
+// are considered the same page.
+type Normalizer struct {
+	ignoredParams []string
+	removeIndex   bool
+}
+
+// NewNormalizer returns a new Normalizer that removes the ignoredParams from the URL's
+// query. Parameters are compared case-insensitively and a parameter ending in "*" matches
+// any parameter starting with the same prefix. If removeIndex is true the default index
+// documents, such as index.html, are removed from the URL's path.
+func NewNormalizer(ignoredParams []string, removeIndex bool) *Normalizer {
+	n := &Normalizer{removeIndex: removeIndex}
+	for _, p := range ignoredParams {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p != "" {
+			n.ignoredParams = append(n.ignoredParams, p)
+		}
+	}
+
+	return n
+}
+
+// Normalize returns a normalized copy of the URL. It lowercases the host, removes the
+// fragment, removes the ignored query parameters and sorts the remaining ones.
+func (n *Normalizer) Normalize(u *url.URL) *url.URL {
+	nu := *u
+	nu.Host = strings.ToLower(nu.Host)
+	nu.Fragment = ""
+	nu.RawFragment = ""
+	nu.ForceQuery = false
+
+	if nu.Path == "" && nu.Opaque == "" {
+		nu.Path = "/"
+	}
+
+	if n.removeIndex && indexDocuments[strings.ToLower(path.Base(nu.Path))] {
+		nu.Path = strings.TrimSuffix(nu.Path, path.Base(nu.Path))
+		nu.RawPath = ""
+	}
+
+	nu.RawQuery = n.normalizeQuery(nu.RawQuery)
+
+	return &nu
+}
+
+// normalizeQuery removes the ignored parameters and sorts the remaining ones by name.
+// The parameters are kept in their original encoding and parameters with the same name
+// keep their relative order.
+func (n *Normalizer) normalizeQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return ""
+	}
+
+	params := []string{}
+	for _, p := range strings.Split(rawQuery, "&") {
+		if p == "" || n.isIgnored(paramName(p)) {
+			continue
+		}
+
+		params = append(params, p)
+	}
+
+	sort.SliceStable(params, func(i, j int) bool {
+		return paramName(params[i]) < paramName(params[j])
+	})
+
+	return strings.Join(params, "&")
+}
+
+// isIgnored returns true if the parameter name matches any of the ignored parameters.
+func (n *Normalizer) isIgnored(name string) bool {
+	name = strings.ToLower(name)
+	if unescaped, err := url.QueryUnescape(name); err == nil {
+		name = unescaped
+	}
+
+	for _, p := range n.ignoredParams {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(name, prefix) {
+			return true
+		}
+
+		if p == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// paramName returns the name of a raw query parameter.
+func paramName(p string) string {
+	name, _, _ := strings.Cut(p, "=")
+
+	return name
+}
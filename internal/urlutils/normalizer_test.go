@@ -0,0 +1,57 @@
+package urlutils_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/urlutils"
+)
+
+// Test Normalize with the default ignored parameters and the index documents removal.
+func TestNormalize(t *testing.T) {
+	normalizer := urlutils.NewNormalizer(urlutils.DefaultIgnoredParams, true)
+
+	table := []struct {
+		urlStr string
+		want   string
+	}{
+		{"https://EXAMPLE.com", "https://example.com/"},
+		{"https://example.com/page#section", "https://example.com/page"},
+		{"https://example.com/page?", "https://example.com/page"},
+		{"https://example.com/page?utm_source=x&utm_medium=y", "https://example.com/page"},
+		{"https://example.com/page?b=2&a=1&UTM_Campaign=z", "https://example.com/page?a=1&b=2"},
+		{"https://example.com/page?b=2&a=2&a=1", "https://example.com/page?a=2&a=1&b=2"},
+		{"https://example.com/page?PHPSESSID=abc&id=1", "https://example.com/page?id=1"},
+		{"https://example.com/page?q=a%20b&flag", "https://example.com/page?flag&q=a%20b"},
+		{"https://example.com/blog/index.html", "https://example.com/blog/"},
+		{"https://example.com/Index.PHP?p=1", "https://example.com/?p=1"},
+		{"https://example.com/index.html.bak", "https://example.com/index.html.bak"},
+	}
+
+	for _, tc := range table {
+		u, err := url.Parse(tc.urlStr)
+		if err != nil {
+			t.Fatalf("error parsing url: %v", err)
+		}
+
+		got := normalizer.Normalize(u).String()
+		if got != tc.want {
+			t.Errorf("Normalize(%s): want %s got %s", tc.urlStr, tc.want, got)
+		}
+	}
+}
+
+// Test Normalize keeps the index documents if the option is not set.
+func TestNormalizeKeepIndex(t *testing.T) {
+	normalizer := urlutils.NewNormalizer(nil, false)
+
+	u, err := url.Parse("https://example.com/index.html?utm_source=x")
+	if err != nil {
+		t.Fatalf("error parsing url: %v", err)
+	}
+
+	want := "https://example.com/index.html?utm_source=x"
+	if got := normalizer.Normalize(u).String(); got != want {
+		t.Errorf("Normalize: want %s got %s", want, got)
+	}
+}
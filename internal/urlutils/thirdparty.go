@@ -0,0 +1,32 @@
+package urlutils
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+// SiteDomain returns the registrable domain of a host, for instance example.co.uk for
+// www.example.co.uk. IP addresses and hosts without a public suffix are returned as they are.
+func SiteDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+
+	d, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+
+	return d
+}
+
+// ThirdParty returns true if the resource URL belongs to a different site than the page URL.
+// Subdomains of the page's registrable domain, such as a static or cdn subdomain, are not
+// considered third-party.
+func ThirdParty(pageURL, resourceURL *url.URL) bool {
+	return SiteDomain(pageURL.Hostname()) != SiteDomain(resourceURL.Hostname())
+}
@@ -0,0 +1,52 @@
+package urlutils_test
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stjudewashere/seonaut/internal/urlutils"
+)
+
+// Test SiteDomain returns the registrable domain of the hosts.
+func TestSiteDomain(t *testing.T) {
+	table := []struct {
+		host string
+		want string
+	}{
+		{"example.com", "example.com"},
+		{"www.example.com", "example.com"},
+		{"cdn.static.example.co.uk", "example.co.uk"},
+		{"WWW.Example.COM", "example.com"},
+		{"127.0.0.1", "127.0.0.1"},
+		{"localhost", "localhost"},
+	}
+
+	for _, test := range table {
+		if got := urlutils.SiteDomain(test.host); got != test.want {
+			t.Errorf("SiteDomain %s: %s != %s", test.host, got, test.want)
+		}
+	}
+}
+
+// Test ThirdParty with resources in the same site, its subdomains and other sites.
+func TestThirdParty(t *testing.T) {
+	pageURL, _ := url.Parse("https://www.example.com/page")
+
+	table := []struct {
+		resource string
+		want     bool
+	}{
+		{"https://www.example.com/app.js", false},
+		{"https://cdn.example.com/app.js", false},
+		{"https://example.com:8080/app.js", false},
+		{"https://cdn.jsdelivr.net/npm/lib.js", true},
+		{"https://example.org/app.js", true},
+	}
+
+	for _, test := range table {
+		resourceURL, _ := url.Parse(test.resource)
+		if got := urlutils.ThirdParty(pageURL, resourceURL); got != test.want {
+			t.Errorf("ThirdParty %s: %v != %v", test.resource, got, test.want)
+		}
+	}
+}
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS `crawl_checkpoints`;
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS `crawl_checkpoints` (
+  `crawl_id` int unsigned NOT NULL,
+  `last_pagereport_id` int unsigned NOT NULL DEFAULT '0',
+  `data` longblob NOT NULL,
+  `updated` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`crawl_id`),
+  CONSTRAINT `crawl_checkpoints_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE
+);
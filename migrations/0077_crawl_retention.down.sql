@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_retention`;
+
+ALTER TABLE `crawls` DROP COLUMN `pruned`;
\ No newline at end of file
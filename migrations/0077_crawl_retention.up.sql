@@ -0,0 +1,6 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_retention` int NOT NULL DEFAULT '1';
+
+ALTER TABLE `crawls` ADD COLUMN `pruned` tinyint NOT NULL DEFAULT '0';
+
+UPDATE `crawls` LEFT JOIN (SELECT MAX(id) AS id FROM `crawls` GROUP BY project_id) AS `last` ON `last`.id = `crawls`.id
+SET `crawls`.pruned = 1 WHERE `last`.id IS NULL;
\ No newline at end of file
@@ -0,0 +1 @@
+drop index pagereports_crawl_hash on pagereports;
\ No newline at end of file
@@ -0,0 +1 @@
+create index pagereports_crawl_hash on pagereports(crawl_id, url_hash);
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_schedule`;
+
+ALTER TABLE `projects` DROP COLUMN `next_crawl`;
\ No newline at end of file
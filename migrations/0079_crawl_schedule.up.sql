@@ -0,0 +1,3 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_schedule` varchar(256) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `next_crawl` datetime NULL;
\ No newline at end of file
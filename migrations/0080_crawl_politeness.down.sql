@@ -0,0 +1,9 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_workers`;
+
+ALTER TABLE `projects` DROP COLUMN `crawl_min_delay`;
+
+ALTER TABLE `projects` DROP COLUMN `crawl_max_delay`;
+
+ALTER TABLE `projects` DROP COLUMN `crawl_max_rps`;
+
+ALTER TABLE `projects` DROP COLUMN `crawl_timeout`;
\ No newline at end of file
@@ -0,0 +1,9 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_workers` int NOT NULL DEFAULT '2';
+
+ALTER TABLE `projects` ADD COLUMN `crawl_min_delay` int NOT NULL DEFAULT '0';
+
+ALTER TABLE `projects` ADD COLUMN `crawl_max_delay` int NOT NULL DEFAULT '1500';
+
+ALTER TABLE `projects` ADD COLUMN `crawl_max_rps` double NOT NULL DEFAULT '0';
+
+ALTER TABLE `projects` ADD COLUMN `crawl_timeout` int NOT NULL DEFAULT '120';
\ No newline at end of file
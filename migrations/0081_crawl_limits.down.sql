@@ -0,0 +1,5 @@
+ALTER TABLE `projects` DROP COLUMN `crawl_limit`;
+
+ALTER TABLE `projects` DROP COLUMN `max_depth`;
+
+ALTER TABLE `crawls` DROP COLUMN `depth_limited`;
\ No newline at end of file
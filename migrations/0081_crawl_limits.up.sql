@@ -0,0 +1,5 @@
+ALTER TABLE `projects` ADD COLUMN `crawl_limit` int NOT NULL DEFAULT '20000';
+
+ALTER TABLE `projects` ADD COLUMN `max_depth` int NOT NULL DEFAULT '0';
+
+ALTER TABLE `crawls` ADD COLUMN `depth_limited` int NOT NULL DEFAULT '0';
\ No newline at end of file
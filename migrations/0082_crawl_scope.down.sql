@@ -0,0 +1,5 @@
+ALTER TABLE `projects` DROP COLUMN `include_rules`;
+
+ALTER TABLE `projects` DROP COLUMN `exclude_rules`;
+
+ALTER TABLE `crawls` DROP COLUMN `out_of_scope`;
\ No newline at end of file
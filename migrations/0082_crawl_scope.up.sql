@@ -0,0 +1,5 @@
+ALTER TABLE `projects` ADD COLUMN `include_rules` varchar(2048) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `exclude_rules` varchar(2048) NOT NULL DEFAULT '';
+
+ALTER TABLE `crawls` ADD COLUMN `out_of_scope` int NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `ignored_params`;
+
+ALTER TABLE `projects` DROP COLUMN `remove_index_documents`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `projects` ADD COLUMN `ignored_params` varchar(1024) NOT NULL DEFAULT 'utm_*,gclid,fbclid,msclkid,phpsessid,jsessionid,aspsessionid*,sessionid,sid';
+
+ALTER TABLE `projects` ADD COLUMN `remove_index_documents` tinyint NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `crawls` DROP COLUMN `mode`;
\ No newline at end of file
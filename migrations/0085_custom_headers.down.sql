@@ -0,0 +1,3 @@
+ALTER TABLE `projects` DROP COLUMN `custom_headers`;
+
+ALTER TABLE `projects` DROP COLUMN `custom_cookies`;
\ No newline at end of file
@@ -0,0 +1,3 @@
+ALTER TABLE `projects` ADD COLUMN `custom_headers` varchar(2048) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `custom_cookies` varchar(2048) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE `projects` DROP COLUMN `login_url`;
+
+ALTER TABLE `projects` DROP COLUMN `login_user_field`;
+
+ALTER TABLE `projects` DROP COLUMN `login_pass_field`;
+
+ALTER TABLE `projects` DROP COLUMN `login_user`;
+
+ALTER TABLE `projects` DROP COLUMN `login_success`;
+
+ALTER TABLE `projects` DROP COLUMN `login_logged_out`;
+
+ALTER TABLE `crawls` DROP COLUMN `login_failed`;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE `projects` ADD COLUMN `login_url` varchar(2048) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `login_user_field` varchar(256) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `login_pass_field` varchar(256) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `login_user` varchar(256) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `login_success` varchar(256) NOT NULL DEFAULT '';
+
+ALTER TABLE `projects` ADD COLUMN `login_logged_out` varchar(256) NOT NULL DEFAULT '';
+
+ALTER TABLE `crawls` ADD COLUMN `login_failed` tinyint NOT NULL DEFAULT 0;
\ No newline at end of file
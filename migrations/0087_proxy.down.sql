@@ -0,0 +1 @@
+ALTER TABLE `projects` DROP COLUMN `proxy`;
\ No newline at end of file
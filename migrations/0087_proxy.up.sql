@@ -0,0 +1 @@
+ALTER TABLE `projects` ADD COLUMN `proxy` varchar(2048) NOT NULL DEFAULT '';
\ No newline at end of file
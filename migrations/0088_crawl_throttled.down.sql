@@ -0,0 +1 @@
+ALTER TABLE `crawls` DROP COLUMN `throttled`;
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `crawls` ADD COLUMN `throttled` int NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE `projects` DROP COLUMN `request_timeout`;
+
+ALTER TABLE `projects` DROP COLUMN `request_retries`;
+
+ALTER TABLE `pagereports` DROP COLUMN `attempts`;
\ No newline at end of file
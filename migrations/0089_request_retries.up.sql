@@ -0,0 +1,5 @@
+ALTER TABLE `projects` ADD COLUMN `request_timeout` int NOT NULL DEFAULT '10';
+
+ALTER TABLE `projects` ADD COLUMN `request_retries` int NOT NULL DEFAULT '2';
+
+ALTER TABLE `pagereports` ADD COLUMN `attempts` int NOT NULL DEFAULT '1';
\ No newline at end of file
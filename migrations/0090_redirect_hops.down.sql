@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `redirect_hops`;
+
+DELETE FROM issue_types WHERE id = 80;
+
+DELETE FROM issue_types WHERE id = 81;
\ No newline at end of file
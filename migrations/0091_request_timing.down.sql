@@ -0,0 +1,11 @@
+ALTER TABLE `pagereports` DROP COLUMN `dns_lookup`;
+
+ALTER TABLE `pagereports` DROP COLUMN `tcp_connect`;
+
+ALTER TABLE `pagereports` DROP COLUMN `tls_handshake`;
+
+ALTER TABLE `pagereports` DROP COLUMN `download`;
+
+ALTER TABLE `pagereports` DROP COLUMN `protocol`;
+
+ALTER TABLE `pagereports` DROP COLUMN `tls_version`;
\ No newline at end of file
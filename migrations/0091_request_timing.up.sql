@@ -0,0 +1,11 @@
+ALTER TABLE `pagereports` ADD COLUMN `dns_lookup` int NOT NULL DEFAULT '0';
+
+ALTER TABLE `pagereports` ADD COLUMN `tcp_connect` int NOT NULL DEFAULT '0';
+
+ALTER TABLE `pagereports` ADD COLUMN `tls_handshake` int NOT NULL DEFAULT '0';
+
+ALTER TABLE `pagereports` ADD COLUMN `download` int NOT NULL DEFAULT '0';
+
+ALTER TABLE `pagereports` ADD COLUMN `protocol` varchar(16) NOT NULL DEFAULT '';
+
+ALTER TABLE `pagereports` ADD COLUMN `tls_version` varchar(16) NOT NULL DEFAULT '';
\ No newline at end of file
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS `certificates`;
+
+DELETE FROM issue_types WHERE id = 82;
+
+DELETE FROM issue_types WHERE id = 83;
+
+DELETE FROM issue_types WHERE id = 84;
+
+DELETE FROM issue_types WHERE id = 85;
\ No newline at end of file
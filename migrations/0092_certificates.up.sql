@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS `certificates` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `crawl_id` int unsigned NOT NULL,
+  `pagereport_id` int unsigned NOT NULL,
+  `host` varchar(256) NOT NULL DEFAULT '',
+  `subject` varchar(1024) NOT NULL DEFAULT '',
+  `issuer` varchar(1024) NOT NULL DEFAULT '',
+  `not_before` datetime DEFAULT NULL,
+  `not_after` datetime DEFAULT NULL,
+  `dns_names` text,
+  `valid` tinyint NOT NULL DEFAULT '0',
+  `hostname_mismatch` tinyint NOT NULL DEFAULT '0',
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `certificates_crawl_host` (`crawl_id`, `host`),
+  KEY `certificates_pagereport` (`pagereport_id`),
+  CONSTRAINT `certificates_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `certificates_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(82, "ERROR_CERTIFICATE_EXPIRING", 1);
+
+INSERT INTO issue_types (id, type, priority) VALUES(83, "ERROR_CERTIFICATE_HOSTNAME_MISMATCH", 1);
+
+INSERT INTO issue_types (id, type, priority) VALUES(84, "ERROR_WEAK_TLS_VERSION", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(85, "ERROR_MIXED_CERTIFICATES", 2);
\ No newline at end of file
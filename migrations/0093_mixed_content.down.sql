@@ -0,0 +1,3 @@
+DELETE FROM issue_types WHERE id = 86;
+
+DELETE FROM issue_types WHERE id = 87;
\ No newline at end of file
@@ -0,0 +1,3 @@
+INSERT INTO issue_types (id, type, priority) VALUES(86, "ERROR_ACTIVE_MIXED_CONTENT", 1);
+
+INSERT INTO issue_types (id, type, priority) VALUES(87, "ERROR_PASSIVE_MIXED_CONTENT", 2);
\ No newline at end of file
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS `cookies`;
+
+DELETE FROM issue_types WHERE id = 88;
+
+DELETE FROM issue_types WHERE id = 89;
+
+DELETE FROM issue_types WHERE id = 90;
+
+DELETE FROM issue_types WHERE id = 91;
+
+DELETE FROM issue_types WHERE id = 92;
+
+DELETE FROM issue_types WHERE id = 93;
+
+DELETE FROM issue_types WHERE id = 94;
\ No newline at end of file
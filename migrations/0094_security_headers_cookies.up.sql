@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS `cookies` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `name` varchar(256) NOT NULL DEFAULT '',
+  `domain` varchar(256) NOT NULL DEFAULT '',
+  `secure` tinyint NOT NULL DEFAULT '0',
+  `http_only` tinyint NOT NULL DEFAULT '0',
+  `same_site` varchar(16) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `cookies_pagereport` (`pagereport_id`),
+  KEY `cookies_crawl` (`crawl_id`),
+  CONSTRAINT `cookies_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `cookies_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(88, "ERROR_MISSING_REFERRER_POLICY", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(89, "ERROR_MISSING_PERMISSIONS_POLICY", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(90, "ERROR_MISSING_FRAME_OPTIONS", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(91, "ERROR_UNSAFE_CSP", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(92, "ERROR_COOKIE_WITHOUT_SECURE", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(93, "ERROR_COOKIE_WITHOUT_HTTPONLY", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(94, "ERROR_COOKIE_WITHOUT_SAMESITE", 3);
\ No newline at end of file
@@ -0,0 +1 @@
+DELETE FROM issue_types WHERE id = 95;
\ No newline at end of file
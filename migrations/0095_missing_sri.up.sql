@@ -0,0 +1 @@
+INSERT INTO issue_types (id, type, priority) VALUES(95, "ERROR_MISSING_SRI", 3);
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS `structured_data`;
+
+DELETE FROM issue_types WHERE id = 96;
+
+DELETE FROM issue_types WHERE id = 97;
+
+DELETE FROM issue_types WHERE id = 98;
\ No newline at end of file
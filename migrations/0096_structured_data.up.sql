@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS `structured_data` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `format` varchar(16) NOT NULL DEFAULT '',
+  `type` varchar(256) NOT NULL DEFAULT '',
+  `raw` mediumtext,
+  `error` varchar(1024) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `structured_data_pagereport` (`pagereport_id`),
+  KEY `structured_data_crawl` (`crawl_id`),
+  CONSTRAINT `structured_data_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `structured_data_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(96, "ERROR_INVALID_JSON_LD", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(97, "ERROR_STRUCTURED_DATA_MISSING_PROPERTIES", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(98, "ERROR_STRUCTURED_DATA_CONFLICT", 3);
\ No newline at end of file
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS `social_tags`;
+
+DELETE FROM issue_types WHERE id = 99;
+
+DELETE FROM issue_types WHERE id = 100;
+
+DELETE FROM issue_types WHERE id = 101;
+
+DELETE FROM issue_types WHERE id = 102;
+
+DELETE FROM issue_types WHERE id = 103;
+
+DELETE FROM issue_types WHERE id = 104;
\ No newline at end of file
@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS `social_tags` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `property` varchar(128) NOT NULL DEFAULT '',
+  `content` varchar(2048) NOT NULL DEFAULT '',
+  `url` varchar(2048) NOT NULL DEFAULT '',
+  `url_hash` varchar(256) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `social_tags_pagereport` (`pagereport_id`),
+  KEY `social_tags_crawl_property` (`crawl_id`, `property`),
+  KEY `social_tags_url_hash` (`url_hash`),
+  CONSTRAINT `social_tags_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `social_tags_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(99, "ERROR_MISSING_OG_TITLE", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(100, "ERROR_MISSING_OG_IMAGE", 3);
+
+INSERT INTO issue_types (id, type, priority) VALUES(101, "ERROR_OG_URL_CANONICAL_MISMATCH", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(102, "ERROR_OG_IMAGE_RELATIVE", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(103, "ERROR_OG_IMAGE_BROKEN", 2);
+
+INSERT INTO issue_types (id, type, priority) VALUES(104, "ERROR_DUPLICATED_OG_TITLE", 3);
\ No newline at end of file
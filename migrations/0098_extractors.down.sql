@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `extractions`;
+
+ALTER TABLE `projects` DROP COLUMN `extractors`;
\ No newline at end of file
@@ -0,0 +1,14 @@
+ALTER TABLE `projects` ADD COLUMN `extractors` varchar(4096) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS `extractions` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `name` varchar(64) NOT NULL DEFAULT '',
+  `value` varchar(1024) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `extractions_pagereport` (`pagereport_id`),
+  KEY `extractions_crawl_name` (`crawl_id`, `name`),
+  CONSTRAINT `extractions_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `extractions_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `custom_search`;
+
+ALTER TABLE `projects` DROP COLUMN `custom_search`;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE `projects` ADD COLUMN `custom_search` varchar(4096) NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS `custom_search` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `name` varchar(64) NOT NULL DEFAULT '',
+  PRIMARY KEY (`id`),
+  KEY `custom_search_pagereport` (`pagereport_id`),
+  KEY `custom_search_crawl_name` (`crawl_id`, `name`),
+  CONSTRAINT `custom_search_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `custom_search_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
\ No newline at end of file
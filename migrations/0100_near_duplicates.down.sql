@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS `near_duplicates`;
+
+ALTER TABLE `pagereports` DROP COLUMN `simhash`;
+
+DELETE FROM issue_types WHERE id = 105;
\ No newline at end of file
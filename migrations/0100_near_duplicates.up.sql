@@ -0,0 +1,17 @@
+ALTER TABLE `pagereports` ADD COLUMN `simhash` bigint unsigned NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS `near_duplicates` (
+  `id` int unsigned NOT NULL AUTO_INCREMENT,
+  `pagereport_id` int unsigned NOT NULL,
+  `crawl_id` int unsigned NOT NULL,
+  `match_pagereport_id` int unsigned NOT NULL,
+  `similarity` tinyint unsigned NOT NULL DEFAULT 0,
+  `cluster` int unsigned NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `near_duplicates_pagereport` (`pagereport_id`),
+  KEY `near_duplicates_crawl_cluster` (`crawl_id`, `cluster`),
+  CONSTRAINT `near_duplicates_crawl` FOREIGN KEY (`crawl_id`) REFERENCES `crawls` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `near_duplicates_pagereport` FOREIGN KEY (`pagereport_id`) REFERENCES `pagereports` (`id`) ON DELETE CASCADE
+);
+
+INSERT INTO issue_types (id, type, priority) VALUES(105, "ERROR_NEAR_DUPLICATE_CONTENT", 3);
\ No newline at end of file